@@ -1,8 +1,11 @@
 package balanced
 
 import (
+	"context"
 	"fmt"
+	"iter"
 	"net/url"
+	"slices"
 	"time"
 )
 
@@ -10,7 +13,12 @@ const (
 	accountsUri         = marketplaceUri + "/%v/accounts"
 	accountTypePerson   = "person"
 	accountTypeBusiness = "business"
-	merchantRole        = "merchant"
+	merchantRole        = AccountRoleMerchant
+
+	// Accounts with a card to debit
+	AccountRoleBuyer = "buyer"
+	// Accounts that have been underwritten and can be credited
+	AccountRoleMerchant = "merchant"
 )
 
 type Account struct {
@@ -30,6 +38,13 @@ type Account struct {
 	Uri             string    `json:"uri,omitempty"`
 }
 
+type ListOfAccounts = Page[Account]
+
+// Reports whether the account has the given role, i.e. AccountRoleMerchant.
+func (a *Account) HasRole(role string) bool {
+	return slices.Contains(a.Roles, role)
+}
+
 type Merchant struct {
 	PhoneNumber   string    `json:"phone_number,omitempty"`
 	Type          string    `json:"type,omitempty"`
@@ -63,50 +78,250 @@ type Person struct {
 // Accounts help facilitate managing multiple credit cards, debit cards, and
 // bank accounts along with different financial transaction operations, i.e.
 // refunds, debits, credits.
+func (c *Client) CreateAccount() (account *Account, err error) {
+	return c.CreateAccountContext(context.Background())
+}
+
+// CreateAccountContext is like CreateAccount but carries the given context.
+func (c *Client) CreateAccountContext(ctx context.Context) (account *Account, err error) {
+	uri := fmt.Sprintf(accountsUri, c.MarketplaceId)
+
+	account = &Account{}
+	err = c.post(ctx, uri, nil, account)
+
+	return
+}
+
+// CreateAccount is a wrapper around DefaultClient.CreateAccount.
 func CreateAccount() (account *Account, err error) {
-	uri := fmt.Sprintf(accountsUri, marketplaceId)
+	return DefaultClient.CreateAccount()
+}
+
+// CreateAccountContext is a wrapper around DefaultClient.CreateAccountContext.
+func CreateAccountContext(ctx context.Context) (account *Account, err error) {
+	return DefaultClient.CreateAccountContext(ctx)
+}
+
+// Retrieves the details of an account that has previously been created.
+func (c *Client) RetrieveAccount(uri string) (account *Account, err error) {
+	return c.RetrieveAccountContext(context.Background(), uri)
+}
+
+// RetrieveAccountContext is like RetrieveAccount but carries the given context.
+func (c *Client) RetrieveAccountContext(ctx context.Context, uri string) (account *Account, err error) {
+	account = &Account{}
+	err = c.get(ctx, uri, nil, account)
+
+	return
+}
+
+// RetrieveAccount is a wrapper around DefaultClient.RetrieveAccount.
+func RetrieveAccount(uri string) (account *Account, err error) {
+	return DefaultClient.RetrieveAccount(uri)
+}
+
+// RetrieveAccountContext is a wrapper around DefaultClient.RetrieveAccountContext.
+func RetrieveAccountContext(ctx context.Context, uri string) (account *Account, err error) {
+	return DefaultClient.RetrieveAccountContext(ctx, uri)
+}
+
+// Retrieves the account with the given email address. Email addresses are
+// unique within a marketplace. Returns an error matching ErrNotFound if no
+// account has it.
+func (c *Client) RetrieveAccountByEmail(emailAddress string) (account *Account, err error) {
+	return c.RetrieveAccountByEmailContext(context.Background(), emailAddress)
+}
+
+// RetrieveAccountByEmailContext is like RetrieveAccountByEmail but carries the given context.
+func (c *Client) RetrieveAccountByEmailContext(ctx context.Context, emailAddress string) (account *Account, err error) {
+	payload := defaultPayload(1, 0)
+	payload.Set("email_address", emailAddress)
+
+	uri := fmt.Sprintf(accountsUri, c.MarketplaceId)
+
+	listOfAccounts := &ListOfAccounts{}
+	if err = c.get(ctx, uri, payload, listOfAccounts); err != nil {
+		return nil, err
+	}
+
+	if len(listOfAccounts.Items) == 0 {
+		return nil, fmt.Errorf("%w: No account with email address %v",
+			ErrNotFound, emailAddress)
+	}
+
+	return &listOfAccounts.Items[0], nil
+}
+
+// RetrieveAccountByEmail is a wrapper around DefaultClient.RetrieveAccountByEmail.
+func RetrieveAccountByEmail(emailAddress string) (account *Account, err error) {
+	return DefaultClient.RetrieveAccountByEmail(emailAddress)
+}
+
+// RetrieveAccountByEmailContext is a wrapper around DefaultClient.RetrieveAccountByEmailContext.
+func RetrieveAccountByEmailContext(ctx context.Context, emailAddress string) (account *Account, err error) {
+	return DefaultClient.RetrieveAccountByEmailContext(ctx, emailAddress)
+}
+
+// Returns a list of accounts you've created, with the most recent accounts
+// appearing first.
+func (c *Client) ListAllAccounts(limit, offset int) (listOfAccounts *ListOfAccounts, err error) {
+	return c.ListAllAccountsContext(context.Background(), limit, offset)
+}
+
+// ListAllAccountsContext is like ListAllAccounts but carries the given context.
+func (c *Client) ListAllAccountsContext(ctx context.Context, limit, offset int) (listOfAccounts *ListOfAccounts, err error) {
+	return c.ListAllAccountsWithRoleContext(ctx, "", limit, offset)
+}
+
+// ListAllAccounts is a wrapper around DefaultClient.ListAllAccounts.
+func ListAllAccounts(limit, offset int) (listOfAccounts *ListOfAccounts, err error) {
+	return DefaultClient.ListAllAccounts(limit, offset)
+}
+
+// ListAllAccountsContext is a wrapper around DefaultClient.ListAllAccountsContext.
+func ListAllAccountsContext(ctx context.Context, limit, offset int) (listOfAccounts *ListOfAccounts, err error) {
+	return DefaultClient.ListAllAccountsContext(ctx, limit, offset)
+}
+
+// Returns a list of the accounts that have the given role, i.e.
+// AccountRoleMerchant, with the most recent accounts appearing first. An empty
+// role lists every account.
+func (c *Client) ListAllAccountsWithRole(role string, limit, offset int) (listOfAccounts *ListOfAccounts, err error) {
+	return c.ListAllAccountsWithRoleContext(context.Background(), role, limit,
+		offset)
+}
+
+// ListAllAccountsWithRoleContext is like ListAllAccountsWithRole but carries the given context.
+func (c *Client) ListAllAccountsWithRoleContext(ctx context.Context, role string, limit, offset int) (listOfAccounts *ListOfAccounts, err error) {
+	payload := defaultPayload(limit, offset)
+	addToPayload(payload, "roles", role)
+
+	uri := fmt.Sprintf(accountsUri, c.MarketplaceId)
+
+	listOfAccounts = &ListOfAccounts{}
+	err = c.get(ctx, uri, payload, listOfAccounts)
+
+	return
+}
+
+// ListAllAccountsWithRole is a wrapper around DefaultClient.ListAllAccountsWithRole.
+func ListAllAccountsWithRole(role string, limit, offset int) (listOfAccounts *ListOfAccounts, err error) {
+	return DefaultClient.ListAllAccountsWithRole(role, limit, offset)
+}
+
+// ListAllAccountsWithRoleContext is a wrapper around DefaultClient.ListAllAccountsWithRoleContext.
+func ListAllAccountsWithRoleContext(ctx context.Context, role string, limit, offset int) (listOfAccounts *ListOfAccounts, err error) {
+	return DefaultClient.ListAllAccountsWithRoleContext(ctx, role, limit,
+		offset)
+}
+
+// Updates the name, email address and meta of an account. Empty values are
+// left unchanged.
+func (c *Client) UpdateAccount(uri, name, emailAddress string, meta MetaType) (account *Account, err error) {
+	return c.UpdateAccountContext(context.Background(), uri, name,
+		emailAddress, meta)
+}
+
+// UpdateAccountContext is like UpdateAccount but carries the given context.
+func (c *Client) UpdateAccountContext(ctx context.Context, uri, name, emailAddress string, meta MetaType) (account *Account, err error) {
+	payload := url.Values{}
+
+	addToPayload(payload, "name", name)
+	addToPayload(payload, "email_address", emailAddress)
+
+	for key, value := range meta {
+		addToPayload(payload, "meta["+key+"]", value)
+	}
 
 	account = &Account{}
-	err = post(uri, nil, account)
+	err = c.put(ctx, uri, payload, account)
 
 	return
 }
 
+// UpdateAccount is a wrapper around DefaultClient.UpdateAccount.
+func UpdateAccount(uri, name, emailAddress string, meta MetaType) (account *Account, err error) {
+	return DefaultClient.UpdateAccount(uri, name, emailAddress, meta)
+}
+
+// UpdateAccountContext is a wrapper around DefaultClient.UpdateAccountContext.
+func UpdateAccountContext(ctx context.Context, uri, name, emailAddress string, meta MetaType) (account *Account, err error) {
+	return DefaultClient.UpdateAccountContext(ctx, uri, name, emailAddress,
+		meta)
+}
+
 // Adding a card to an account activates the ability to debit an account,
 // more specifically, charging a card.You can add multiple cards to an account.
 // Balanced associates a buyer role to signify whether or not an account has a
 // valid credit card, to acquire funds from.
-func AddCardToAccount(uri, cardUri string) (account *Account, err error) {
+func (c *Client) AddCardToAccount(uri, cardUri string) (account *Account, err error) {
+	return c.AddCardToAccountContext(context.Background(), uri, cardUri)
+}
+
+// AddCardToAccountContext is like AddCardToAccount but carries the given context.
+func (c *Client) AddCardToAccountContext(ctx context.Context, uri, cardUri string) (account *Account, err error) {
 	payload := url.Values{
 		"card_uri": {cardUri},
 	}
 
 	account = &Account{}
-	err = put(uri, payload, account)
+	err = c.put(ctx, uri, payload, account)
 
 	return
 }
 
+// AddCardToAccount is a wrapper around DefaultClient.AddCardToAccount.
+func AddCardToAccount(uri, cardUri string) (account *Account, err error) {
+	return DefaultClient.AddCardToAccount(uri, cardUri)
+}
+
+// AddCardToAccountContext is a wrapper around DefaultClient.AddCardToAccountContext.
+func AddCardToAccountContext(ctx context.Context, uri, cardUri string) (account *Account, err error) {
+	return DefaultClient.AddCardToAccountContext(ctx, uri, cardUri)
+}
+
 // Adding a bank account to an account activates the ability to credit an
 // account, or in this case, initiate a next-day ACH payment.Balanced does not
 // associate a role to signify whether or not an account has a valid bank
 // account to send money to.
-func AddBankAccountToAccount(uri, bankAccountUri string) (account *Account, err error) {
+func (c *Client) AddBankAccountToAccount(uri, bankAccountUri string) (account *Account, err error) {
+	return c.AddBankAccountToAccountContext(context.Background(), uri,
+		bankAccountUri)
+}
+
+// AddBankAccountToAccountContext is like AddBankAccountToAccount but carries the given context.
+func (c *Client) AddBankAccountToAccountContext(ctx context.Context, uri, bankAccountUri string) (account *Account, err error) {
 	payload := url.Values{
 		"bank_account_uri": {bankAccountUri},
 	}
 
 	account = &Account{}
-	err = put(uri, payload, account)
+	err = c.put(ctx, uri, payload, account)
 
 	return
 }
 
+// AddBankAccountToAccount is a wrapper around DefaultClient.AddBankAccountToAccount.
+func AddBankAccountToAccount(uri, bankAccountUri string) (account *Account, err error) {
+	return DefaultClient.AddBankAccountToAccount(uri, bankAccountUri)
+}
+
+// AddBankAccountToAccountContext is a wrapper around DefaultClient.AddBankAccountToAccountContext.
+func AddBankAccountToAccountContext(ctx context.Context, uri, bankAccountUri string) (account *Account, err error) {
+	return DefaultClient.AddBankAccountToAccountContext(ctx, uri,
+		bankAccountUri)
+}
+
 // A person, or an individual, is a US based individual or a sole proprietor.
 // Balanced associates a merchant role to signify whether or not an account has
 // been underwritten.
 // WARNING PCI Compliance required to use this functionality.
-func UnderwriteIndividual(merchant *Merchant) (account *Account, err error) {
+func (c *Client) UnderwriteIndividual(merchant *Merchant) (account *Account, err error) {
+	return c.UnderwriteIndividualContext(context.Background(), merchant)
+}
+
+// UnderwriteIndividualContext is like UnderwriteIndividual but carries the given context.
+func (c *Client) UnderwriteIndividualContext(ctx context.Context, merchant *Merchant) (account *Account, err error) {
 	// Required Parameters
 	payload := url.Values{
 		"merchant[phone_number]":   {merchant.PhoneNumber},
@@ -127,18 +342,33 @@ func UnderwriteIndividual(merchant *Merchant) (account *Account, err error) {
 		addToPayload(payload, "merchant[meta["+key+"]]", value)
 	}
 
-	uri := fmt.Sprintf(accountsUri, marketplaceId)
+	uri := fmt.Sprintf(accountsUri, c.MarketplaceId)
 
 	account = &Account{}
-	err = post(uri, payload, account)
+	err = c.post(ctx, uri, payload, account)
 
 	return
 }
 
+// UnderwriteIndividual is a wrapper around DefaultClient.UnderwriteIndividual.
+func UnderwriteIndividual(merchant *Merchant) (account *Account, err error) {
+	return DefaultClient.UnderwriteIndividual(merchant)
+}
+
+// UnderwriteIndividualContext is a wrapper around DefaultClient.UnderwriteIndividualContext.
+func UnderwriteIndividualContext(ctx context.Context, merchant *Merchant) (account *Account, err error) {
+	return DefaultClient.UnderwriteIndividualContext(ctx, merchant)
+}
+
 // Balanced associates a merchant role to signify whether or not an account has
 // been underwritten.
 // WARNING PCI Compliance required to use this functionality.
-func UnderwriteBusiness(merchant *Merchant, person *Person) (account *Account, err error) {
+func (c *Client) UnderwriteBusiness(merchant *Merchant, person *Person) (account *Account, err error) {
+	return c.UnderwriteBusinessContext(context.Background(), merchant, person)
+}
+
+// UnderwriteBusinessContext is like UnderwriteBusiness but carries the given context.
+func (c *Client) UnderwriteBusinessContext(ctx context.Context, merchant *Merchant, person *Person) (account *Account, err error) {
 	// Required Parameters
 	payload := url.Values{
 		"merchant[phone_number]":           {merchant.PhoneNumber},
@@ -166,10 +396,46 @@ func UnderwriteBusiness(merchant *Merchant, person *Person) (account *Account, e
 	addToPayload(payload, "merchant[person[country_code]]", person.CountryCode)
 	addToPayload(payload, "merchant[person[tax_id]]", person.TaxId)
 
-	uri := fmt.Sprintf(accountsUri, marketplaceId)
+	uri := fmt.Sprintf(accountsUri, c.MarketplaceId)
 
 	account = &Account{}
-	err = post(uri, payload, account)
+	err = c.post(ctx, uri, payload, account)
 
 	return
 }
+
+// UnderwriteBusiness is a wrapper around DefaultClient.UnderwriteBusiness.
+func UnderwriteBusiness(merchant *Merchant, person *Person) (account *Account, err error) {
+	return DefaultClient.UnderwriteBusiness(merchant, person)
+}
+
+// UnderwriteBusinessContext is a wrapper around DefaultClient.UnderwriteBusinessContext.
+func UnderwriteBusinessContext(ctx context.Context, merchant *Merchant, person *Person) (account *Account, err error) {
+	return DefaultClient.UnderwriteBusinessContext(ctx, merchant, person)
+}
+
+// Iterates over every account you've created, most recent first.
+func (c *Client) IterateAccounts(ctx context.Context, options *IterOptions) iter.Seq2[Account, error] {
+	return c.IterateAccountsWithRole(ctx, "", options)
+}
+
+// IterateAccounts is a wrapper around DefaultClient.IterateAccounts.
+func IterateAccounts(ctx context.Context, options *IterOptions) iter.Seq2[Account, error] {
+	return DefaultClient.IterateAccounts(ctx, options)
+}
+
+// Iterates over every account that has the given role, most recent first. An
+// empty role iterates over every account.
+func (c *Client) IterateAccountsWithRole(ctx context.Context, role string, options *IterOptions) iter.Seq2[Account, error] {
+	uri := fmt.Sprintf(accountsUri, c.MarketplaceId)
+	if len(role) != 0 {
+		uri += "?" + url.Values{"roles": {role}}.Encode()
+	}
+
+	return Iterate[Account](ctx, c, uri, options)
+}
+
+// IterateAccountsWithRole is a wrapper around DefaultClient.IterateAccountsWithRole.
+func IterateAccountsWithRole(ctx context.Context, role string, options *IterOptions) iter.Seq2[Account, error] {
+	return DefaultClient.IterateAccountsWithRole(ctx, role, options)
+}
@@ -1,6 +1,8 @@
 package balanced
 
 import (
+	"context"
+	"errors"
 	"testing"
 )
 
@@ -26,7 +28,7 @@ func TestAddCardToAccount(t *testing.T) {
 	}
 
 	// Create Card, with minimum requrirements
-	card, err := TokenizeCard(2020, 01, testVisaCard, "", "", "", "", "", "", "", "", nil)
+	card, err := TokenizeCard(testExpirationYear, 01, testVisaCard, "", "", "", "", "", "", "", "", nil)
 	if err != nil {
 		t.Fatalf("Unable to create card: %v", err)
 	}
@@ -108,3 +110,98 @@ func TestUnderwriteBusiness(t *testing.T) {
 		t.Fatalf("Unable to underwrite business, missing merchant role")
 	}
 }
+
+func TestUpdateAccount(t *testing.T) {
+	account, err := CreateAccount()
+	if err != nil {
+		t.Fatalf("Unable to create account: %v", err)
+	}
+
+	email := "peter.sherman+" + account.Id + "@example.com"
+	account, err = UpdateAccount(account.Uri, "Peter Sherman", email,
+		MetaType{"customer_id": "42"})
+	if err != nil {
+		t.Fatalf("Unable to update account: %v", err)
+	}
+
+	if account.Name != "Peter Sherman" || account.EmailAddress != email ||
+		account.Meta["customer_id"] != "42" {
+		t.Fatalf("Invalid account updated: %v", account)
+	}
+
+	retrieved, err := RetrieveAccount(account.Uri)
+	if err != nil {
+		t.Fatalf("Unable to retrieve account: %v", err)
+	}
+
+	if retrieved.Uri != account.Uri || retrieved.Name != "Peter Sherman" {
+		t.Fatalf("Invalid account retrieved: %v", retrieved)
+	}
+
+	found, err := RetrieveAccountByEmail(email)
+	if err != nil {
+		t.Fatalf("Unable to retrieve account by email: %v", err)
+	}
+
+	if found.Uri != account.Uri {
+		t.Fatalf("Incorrect account found by email: %v", found)
+	}
+
+	_, err = RetrieveAccountByEmail("nobody+" + account.Id + "@example.com")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected no account to be found, got %v", err)
+	}
+}
+
+func TestListAllAccounts(t *testing.T) {
+	buyer := createAccountWithCard(t, testVisaCard)
+
+	merchant, err := UnderwriteIndividual(&Merchant{
+		PhoneNumber:   "+14089999999",
+		Name:          "Timmy Q. CopyPasta",
+		Dob:           "1989-12",
+		PostalCode:    "94110",
+		StreetAddress: "121 Skriptkid Row",
+	})
+	if err != nil {
+		t.Fatalf("Unable to underwrite individual: %v", err)
+	}
+
+	list, err := ListAllAccounts(10, 0)
+	if err != nil {
+		t.Fatalf("Unable to list accounts: %v", err)
+	}
+
+	if len(list.Items) < 2 || list.Items[0].Uri != merchant.Uri ||
+		list.Items[1].Uri != buyer.Uri {
+		t.Fatalf("Invalid list of accounts: %v", list.Items)
+	}
+
+	list, err = ListAllAccountsWithRole(AccountRoleMerchant, 10, 0)
+	if err != nil {
+		t.Fatalf("Unable to list merchants: %v", err)
+	}
+
+	for _, account := range list.Items {
+		if !account.HasRole(AccountRoleMerchant) || account.Uri == buyer.Uri {
+			t.Fatalf("Expected only merchants, got %v", account)
+		}
+	}
+
+	found := false
+	for account, err := range IterateAccountsWithRole(context.Background(),
+		AccountRoleBuyer, &IterOptions{PageSize: 2}) {
+		if err != nil {
+			t.Fatalf("Unable to iterate over buyers: %v", err)
+		}
+
+		if !account.HasRole(AccountRoleBuyer) {
+			t.Fatalf("Expected only buyers, got %v", account)
+		}
+		found = found || account.Uri == buyer.Uri
+	}
+
+	if !found {
+		t.Fatal("Buyer missing from buyers")
+	}
+}
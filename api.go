@@ -2,10 +2,12 @@ package balanced
 
 import (
 	"fmt"
+	"net/http"
 )
 
 // Custom Error to handle balanced api responses. Implements
-// error interface
+// error interface. Operations return it as a *ApiError, use errors.As to
+// inspect it or errors.Is with the Err* values to classify it.
 type ApiError struct {
 	Additional   string `json:"additional,omitempty"`
 	CategoryType string `json:"category_type,omitempty"`
@@ -27,7 +29,30 @@ func (e ApiError) Error() string {
 		e.CategoryType, e.CategoryCode, e.Extras)
 }
 
+// Classifies the error, so that errors.Is(err, ErrCardDeclined) and friends
+// work on errors returned by the api.
+func (e ApiError) Is(target error) bool {
+	switch target {
+	case ErrCardDeclined:
+		return e.CategoryCode == "card-declined" ||
+			(e.StatusCode == http.StatusPaymentRequired &&
+				e.CategoryCode != "insufficient-funds")
+	case ErrInsufficientFunds:
+		return e.CategoryCode == "insufficient-funds"
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized ||
+			e.StatusCode == http.StatusForbidden
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest ||
+			e.StatusCode == http.StatusUnprocessableEntity
+	}
+
+	return false
+}
+
 type ApiDefaultResponse struct {
-	_type string                       `json:"_type,omitempty"`
-	_uris map[string]map[string]string `json:"_uris,omitempty"`
+	ResourceType string                       `json:"_type,omitempty"`
+	ResourceUris map[string]map[string]string `json:"_uris,omitempty"`
 }
@@ -1,6 +1,12 @@
 package balanced
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -16,3 +22,274 @@ type ApiKey struct {
 	Uri       string    `json:"uri,omitempty"`
 	Id        string    `json:"id,omitempty"`
 }
+
+type ListOfApiKeys = Page[ApiKey]
+
+// Creates a new api key. The key's Secret is only returned by this call, keep
+// it somewhere safe.
+func (c *Client) CreateApiKey(meta MetaType) (apiKey *ApiKey, err error) {
+	return c.CreateApiKeyContext(context.Background(), meta)
+}
+
+// CreateApiKeyContext is like CreateApiKey but carries the given context.
+func (c *Client) CreateApiKeyContext(ctx context.Context, meta MetaType) (apiKey *ApiKey, err error) {
+	payload := url.Values{}
+	addMetaToPayload(payload, meta)
+
+	apiKey = &ApiKey{}
+	err = c.post(ctx, apiKeyUri, payload, apiKey)
+
+	return
+}
+
+// CreateApiKey is a wrapper around DefaultClient.CreateApiKey.
+func CreateApiKey(meta MetaType) (apiKey *ApiKey, err error) {
+	return DefaultClient.CreateApiKey(meta)
+}
+
+// CreateApiKeyContext is a wrapper around DefaultClient.CreateApiKeyContext.
+func CreateApiKeyContext(ctx context.Context, meta MetaType) (apiKey *ApiKey, err error) {
+	return DefaultClient.CreateApiKeyContext(ctx, meta)
+}
+
+// Retrieves the details of an api key, without its Secret.
+func (c *Client) RetrieveApiKey(uri string) (apiKey *ApiKey, err error) {
+	return c.RetrieveApiKeyContext(context.Background(), uri)
+}
+
+// RetrieveApiKeyContext is like RetrieveApiKey but carries the given context.
+func (c *Client) RetrieveApiKeyContext(ctx context.Context, uri string) (apiKey *ApiKey, err error) {
+	apiKey = &ApiKey{}
+	err = c.get(ctx, uri, nil, apiKey)
+
+	return
+}
+
+// RetrieveApiKey is a wrapper around DefaultClient.RetrieveApiKey.
+func RetrieveApiKey(uri string) (apiKey *ApiKey, err error) {
+	return DefaultClient.RetrieveApiKey(uri)
+}
+
+// RetrieveApiKeyContext is a wrapper around DefaultClient.RetrieveApiKeyContext.
+func RetrieveApiKeyContext(ctx context.Context, uri string) (apiKey *ApiKey, err error) {
+	return DefaultClient.RetrieveApiKeyContext(ctx, uri)
+}
+
+// Returns a list of api keys, without their secrets. The keys are returned in
+// sorted order, with the most recent keys appearing first.
+func (c *Client) ListApiKeys(limit, offset int) (listOfApiKeys *ListOfApiKeys, err error) {
+	return c.ListApiKeysContext(context.Background(), limit, offset)
+}
+
+// ListApiKeysContext is like ListApiKeys but carries the given context.
+func (c *Client) ListApiKeysContext(ctx context.Context, limit, offset int) (listOfApiKeys *ListOfApiKeys, err error) {
+	payload := defaultPayload(limit, offset)
+
+	listOfApiKeys = &ListOfApiKeys{}
+	err = c.get(ctx, apiKeyUri, payload, listOfApiKeys)
+
+	return
+}
+
+// ListApiKeys is a wrapper around DefaultClient.ListApiKeys.
+func ListApiKeys(limit, offset int) (listOfApiKeys *ListOfApiKeys, err error) {
+	return DefaultClient.ListApiKeys(limit, offset)
+}
+
+// ListApiKeysContext is a wrapper around DefaultClient.ListApiKeysContext.
+func ListApiKeysContext(ctx context.Context, limit, offset int) (listOfApiKeys *ListOfApiKeys, err error) {
+	return DefaultClient.ListApiKeysContext(ctx, limit, offset)
+}
+
+// Deletes an api key. Requests made with its secret are no longer accepted.
+func (c *Client) DeleteApiKey(uri string) (err error) {
+	return c.DeleteApiKeyContext(context.Background(), uri)
+}
+
+// DeleteApiKeyContext is like DeleteApiKey but carries the given context.
+func (c *Client) DeleteApiKeyContext(ctx context.Context, uri string) (err error) {
+	err = c.delete(ctx, uri, nil, nil)
+
+	return
+}
+
+// DeleteApiKey is a wrapper around DefaultClient.DeleteApiKey.
+func DeleteApiKey(uri string) (err error) {
+	return DefaultClient.DeleteApiKey(uri)
+}
+
+// DeleteApiKeyContext is a wrapper around DefaultClient.DeleteApiKeyContext.
+func DeleteApiKeyContext(ctx context.Context, uri string) (err error) {
+	return DefaultClient.DeleteApiKeyContext(ctx, uri)
+}
+
+// Iterates over every api key, most recent first.
+func (c *Client) IterateApiKeys(ctx context.Context, options *IterOptions) iter.Seq2[ApiKey, error] {
+	return Iterate[ApiKey](ctx, c, apiKeyUri, options)
+}
+
+// IterateApiKeys is a wrapper around DefaultClient.IterateApiKeys.
+func IterateApiKeys(ctx context.Context, options *IterOptions) iter.Seq2[ApiKey, error] {
+	return DefaultClient.IterateApiKeys(ctx, options)
+}
+
+// Replaces the client's api key with a newly created one, and returns it. The
+// previous key is left in place until FinishApiKeyRotation deletes it.
+//
+// The new key is verified before anything else is changed; if it does not
+// work it is deleted and the client keeps its previous key. Once verified the
+// client switches to it right away. Until gracePeriod has passed, requests
+// that balanced rejects as unauthorized are sent again with the previous key,
+// which covers the time the new key takes to be accepted everywhere.
+//
+// Store the returned key's Secret before calling FinishApiKeyRotation:
+//
+//	apiKey, err := client.RotateApiKey(time.Hour)
+//	...
+//	err = store(apiKey.Secret)
+//	...
+//	err = client.FinishApiKeyRotation(previousKeyUri)
+func (c *Client) RotateApiKey(gracePeriod time.Duration) (apiKey *ApiKey, err error) {
+	return c.RotateApiKeyContext(context.Background(), gracePeriod)
+}
+
+// RotateApiKeyContext is like RotateApiKey but carries the given context.
+func (c *Client) RotateApiKeyContext(ctx context.Context, gracePeriod time.Duration) (apiKey *ApiKey, err error) {
+	apiKey, err = c.CreateApiKeyContext(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify the new key on its own, without falling back to the previous one
+	if _, err = c.withApiKey(apiKey.Secret).RetrieveApiKeyContext(ctx, apiKey.Uri); err != nil {
+		err = fmt.Errorf("Balanced API: Unable to verify new api key: %w", err)
+		if deleteErr := c.DeleteApiKeyContext(ctx, apiKey.Uri); deleteErr != nil {
+			err = errors.Join(err, fmt.Errorf(
+				"Balanced API: Unable to delete unverified api key %v: %w",
+				apiKey.Uri, deleteErr))
+		}
+
+		return nil, err
+	}
+
+	c.keyMu.Lock()
+	c.previousKey = c.ApiKey
+	c.previousKeyUntil = time.Now().Add(gracePeriod)
+	c.ApiKey = apiKey.Secret
+	c.keyMu.Unlock()
+
+	return apiKey, nil
+}
+
+// RotateApiKey is a wrapper around DefaultClient.RotateApiKey.
+func RotateApiKey(gracePeriod time.Duration) (apiKey *ApiKey, err error) {
+	return DefaultClient.RotateApiKey(gracePeriod)
+}
+
+// RotateApiKeyContext is a wrapper around DefaultClient.RotateApiKeyContext.
+func RotateApiKeyContext(ctx context.Context, gracePeriod time.Duration) (apiKey *ApiKey, err error) {
+	return DefaultClient.RotateApiKeyContext(ctx, gracePeriod)
+}
+
+// Completes a rotation started by RotateApiKey: waits out what is left of its
+// grace period, so other processes sharing the previous key have time to move
+// to the new one, then deletes the previous key at previousKeyUri and stops
+// falling back to it.
+func (c *Client) FinishApiKeyRotation(previousKeyUri string) (err error) {
+	return c.FinishApiKeyRotationContext(context.Background(), previousKeyUri)
+}
+
+// FinishApiKeyRotationContext is like FinishApiKeyRotation but carries the
+// given context. When ctx is done during the grace period the previous key is
+// not deleted, and the client stops falling back to it once the grace period
+// is over.
+func (c *Client) FinishApiKeyRotationContext(ctx context.Context, previousKeyUri string) (err error) {
+	c.keyMu.Lock()
+	previousKey := c.previousKey
+	until := c.previousKeyUntil
+	c.keyMu.Unlock()
+
+	if wait := time.Until(until); wait > 0 {
+		if err = sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+
+	// Delete with the new key alone, so a rejected new key is not hidden
+	key, _ := c.apiKeys()
+	if err = c.withApiKey(key).DeleteApiKeyContext(ctx, previousKeyUri); err != nil {
+		return fmt.Errorf("Balanced API: Unable to delete previous api key: %w", err)
+	}
+
+	c.keyMu.Lock()
+	if c.previousKey == previousKey {
+		c.previousKey = ""
+	}
+	c.keyMu.Unlock()
+
+	return nil
+}
+
+// FinishApiKeyRotation is a wrapper around DefaultClient.FinishApiKeyRotation.
+func FinishApiKeyRotation(previousKeyUri string) (err error) {
+	return DefaultClient.FinishApiKeyRotation(previousKeyUri)
+}
+
+// FinishApiKeyRotationContext is a wrapper around
+// DefaultClient.FinishApiKeyRotationContext.
+func FinishApiKeyRotationContext(ctx context.Context, previousKeyUri string) (err error) {
+	return DefaultClient.FinishApiKeyRotationContext(ctx, previousKeyUri)
+}
+
+// Replaces the api key the client sends requests with. Unlike assigning
+// ApiKey, it is safe while the client is in use. Any rotation in progress is
+// abandoned, and the client no longer falls back to its previous key.
+func (c *Client) SetApiKey(key string) {
+	c.keyMu.Lock()
+	defer c.keyMu.Unlock()
+
+	c.ApiKey = key
+	c.previousKey = ""
+}
+
+// Returns the api key to send requests with, and the previous key to fall back
+// to while a rotation's grace period lasts.
+func (c *Client) apiKeys() (key, previousKey string) {
+	c.keyMu.Lock()
+	defer c.keyMu.Unlock()
+
+	if len(c.previousKey) != 0 && time.Now().Before(c.previousKeyUntil) {
+		previousKey = c.previousKey
+	}
+
+	return c.ApiKey, previousKey
+}
+
+// Returns a client with the same settings, that sends requests with key.
+func (c *Client) withApiKey(key string) *Client {
+	return &Client{
+		ApiRoot:       c.ApiRoot,
+		ApiKey:        key,
+		MarketplaceId: c.MarketplaceId,
+		HTTPClient:    c.HTTPClient,
+		Middleware:    c.Middleware,
+		RetryPolicy:   c.RetryPolicy,
+		BankDirectory: c.BankDirectory,
+	}
+}
+
+// Sends requests that balanced rejects as unauthorized again with previousKey.
+func withPreviousKey(next Handler, previousKey string) Handler {
+	return func(ctx context.Context, req *Request) (*Response, error) {
+		resp, err := next(ctx, req)
+		if err != nil || resp.StatusCode != http.StatusUnauthorized {
+			return resp, err
+		}
+
+		retry := *req
+		retry.Header = req.Header.Clone()
+		retry.Header.Set("Authorization", basicAuth(previousKey))
+
+		return next(ctx, &retry)
+	}
+}
@@ -0,0 +1,235 @@
+package balanced
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nimajalali/balanced-go/balancedtest"
+)
+
+// Creates a client for a fresh fake server, along with the uri of its api key.
+func newApiKeyTestClient(t *testing.T) (*Client, string) {
+	server := balancedtest.NewServer()
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL, server.ApiKey, server.MarketplaceId)
+
+	list, err := client.ListApiKeys(10, 0)
+	if err != nil {
+		t.Fatalf("Unable to list api keys: %v", err)
+	}
+	if len(list.Items) != 1 {
+		t.Fatalf("Expected a single api key, got %v", list.Items)
+	}
+
+	return client, list.Items[0].Uri
+}
+
+func TestApiKeyOperations(t *testing.T) {
+	client, _ := newApiKeyTestClient(t)
+
+	apiKey, err := client.CreateApiKey(MetaType{"owner": "ops"})
+	if err != nil {
+		t.Fatalf("Unable to create api key: %v", err)
+	}
+
+	if len(apiKey.Secret) == 0 || apiKey.Meta["owner"] != "ops" {
+		t.Fatalf("Invalid api key created: %v", apiKey)
+	}
+
+	retrieved, err := client.RetrieveApiKey(apiKey.Uri)
+	if err != nil {
+		t.Fatalf("Unable to retrieve api key: %v", err)
+	}
+
+	if retrieved.Id != apiKey.Id || len(retrieved.Secret) != 0 {
+		t.Fatalf("Invalid api key retrieved: %v", retrieved)
+	}
+
+	count := 0
+	for _, err := range client.IterateApiKeys(context.Background(), nil) {
+		if err != nil {
+			t.Fatalf("Unable to iterate over api keys: %v", err)
+		}
+		count++
+	}
+	if count != 2 {
+		t.Fatalf("Expected 2 api keys, got %v", count)
+	}
+
+	if err := client.DeleteApiKey(apiKey.Uri); err != nil {
+		t.Fatalf("Unable to delete api key: %v", err)
+	}
+
+	_, err = client.RetrieveApiKey(apiKey.Uri)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected deleted api key to be gone, got %v", err)
+	}
+
+	deleted := NewClient(client.ApiRoot, apiKey.Secret, client.MarketplaceId)
+	if _, err := deleted.RetrieveCurrentMarketplace(); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("Expected deleted api key to be rejected, got %v", err)
+	}
+}
+
+func TestRotateApiKey(t *testing.T) {
+	client, previousKeyUri := newApiKeyTestClient(t)
+	previousKey := client.ApiKey
+
+	apiKey, err := client.RotateApiKey(0)
+	if err != nil {
+		t.Fatalf("Unable to rotate api key: %v", err)
+	}
+
+	if key, _ := client.apiKeys(); key != apiKey.Secret {
+		t.Fatalf("Client still uses the previous api key")
+	}
+
+	if _, err := client.RetrieveCurrentMarketplace(); err != nil {
+		t.Fatalf("Unable to use rotated api key: %v", err)
+	}
+
+	// The previous key is kept until the rotation is finished
+	previous := NewClient(client.ApiRoot, previousKey, client.MarketplaceId)
+	if _, err := previous.RetrieveCurrentMarketplace(); err != nil {
+		t.Fatalf("Expected previous api key to be kept, got %v", err)
+	}
+
+	if err := client.FinishApiKeyRotation(previousKeyUri); err != nil {
+		t.Fatalf("Unable to finish api key rotation: %v", err)
+	}
+
+	if _, err := previous.RetrieveCurrentMarketplace(); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("Expected previous api key to be deleted, got %v", err)
+	}
+}
+
+func TestRotateApiKeyGracePeriod(t *testing.T) {
+	client, previousKeyUri := newApiKeyTestClient(t)
+	previousKey := client.ApiKey
+
+	// Rejects the new key, as balanced might right after creating it
+	var rejectNewKey, usedPreviousKey atomic.Bool
+	client.Use(func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			auth := req.Header.Get("Authorization")
+			if rejectNewKey.Load() && auth != basicAuth(previousKey) {
+				return &Response{StatusCode: http.StatusUnauthorized,
+					Body: []byte(`{}`)}, nil
+			}
+			if auth == basicAuth(previousKey) {
+				usedPreviousKey.Store(true)
+			}
+
+			return next(ctx, req)
+		}
+	})
+
+	// The new key is returned right away, before the grace period is over
+	apiKey, err := client.RotateApiKey(time.Minute)
+	if err != nil {
+		t.Fatalf("Unable to rotate api key: %v", err)
+	}
+	if key, _ := client.apiKeys(); key != apiKey.Secret {
+		t.Fatal("Client never switched to the new api key")
+	}
+
+	rejectNewKey.Store(true)
+	usedPreviousKey.Store(false)
+
+	if _, err := client.RetrieveCurrentMarketplace(); err != nil {
+		t.Fatalf("Expected fallback to the previous api key, got %v", err)
+	}
+	if !usedPreviousKey.Load() {
+		t.Fatal("Previous api key was not used")
+	}
+
+	// Giving up during the grace period keeps the previous key
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err = client.FinishApiKeyRotationContext(ctx, previousKeyUri)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected rotation to be canceled, got %v", err)
+	}
+
+	previous := NewClient(client.ApiRoot, previousKey, client.MarketplaceId)
+	if _, err := previous.RetrieveCurrentMarketplace(); err != nil {
+		t.Fatalf("Expected previous api key to be kept, got %v", err)
+	}
+}
+
+func TestRotateApiKeyVerification(t *testing.T) {
+	client, previousKeyUri := newApiKeyTestClient(t)
+	previousKey := client.ApiKey
+
+	// Rejects every key but the previous one
+	var failDeletes atomic.Bool
+	client.Use(func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			if req.Header.Get("Authorization") != basicAuth(previousKey) {
+				return &Response{StatusCode: http.StatusUnauthorized,
+					Body: []byte(`{}`)}, nil
+			}
+			if failDeletes.Load() && req.Method == "DELETE" {
+				return &Response{StatusCode: http.StatusServiceUnavailable,
+					Body: []byte(`{}`)}, nil
+			}
+
+			return next(ctx, req)
+		}
+	})
+
+	_, err := client.RotateApiKey(0)
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("Expected new api key to fail verification, got %v", err)
+	}
+
+	if key, _ := client.apiKeys(); key != previousKey {
+		t.Fatal("Client switched to an unverified api key")
+	}
+
+	list, err := client.ListApiKeys(10, 0)
+	if err != nil {
+		t.Fatalf("Unable to list api keys: %v", err)
+	}
+	if len(list.Items) != 1 || list.Items[0].Uri != previousKeyUri {
+		t.Fatalf("Expected only the previous api key to be left, got %v", list.Items)
+	}
+
+	// Failing to delete the unverified key is reported too
+	failDeletes.Store(true)
+
+	_, err = client.RotateApiKey(0)
+	var apiErr *ApiError
+	if !errors.Is(err, ErrUnauthorized) || !errors.As(err, &apiErr) ||
+		!strings.Contains(err.Error(), "Unable to delete unverified api key") {
+		t.Fatalf("Expected the failed delete to be reported, got %v", err)
+	}
+}
+
+func TestSetApiKey(t *testing.T) {
+	client, _ := newApiKeyTestClient(t)
+	key := client.ApiKey
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		for i := 0; i < 10; i++ {
+			client.SetApiKey(key)
+		}
+	}()
+
+	for i := 0; i < 10; i++ {
+		if _, err := client.RetrieveCurrentMarketplace(); err != nil {
+			t.Fatalf("Unable to use api key: %v", err)
+		}
+	}
+	<-done
+}
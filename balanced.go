@@ -1,71 +1,62 @@
 // A Go package that provides bindings to Balanced Payemnts API.
 //
+// Importing the package has no side effects. Before calling any of the
+// package level functions configure DefaultClient with Configure,
+// ConfigureFromEnv, ConfigureFromFile or SetupEnvironment.
+//
 // See https://www.balancedpayments.com/
 package balanced
 
 import (
-	"flag"
-	"github.com/stathat/jconfig"
-	"log"
-	"os"
+	"context"
+	"fmt"
 )
 
 const (
-	testApiRoot = "https://api.balancedpayments.com"
+	DefaultApiRoot = "https://api.balancedpayments.com"
 )
 
-// Basic information needed to connect to the balanced REST API.
-var apiRoot, apiKey, marketplaceId string
-
-func init() {
-	var stage string
-
-	// Get stage flag passed in. Default to test
-	flag.StringVar(&stage, "stage", "test", "flag for deployment stage")
-	flag.Parse()
+// DefaultClient is the client used by the package level functions.
+var DefaultClient = &Client{}
 
-	if stage == "test" {
-		setupTestEnvironment()
-	} else {
-		// Retrieve config from balanced.conf
-		config := jconfig.LoadConfig(stage + "/balanced.conf")
-		apiRoot = config.GetString("balanced_api_root")
-		apiKey = config.GetString("balanced_api_key")
-		marketplaceId = config.GetString("balanced_marketplace_id")
-	}
-}
-
-// Setup basic information needed to connect to balanced. Overrides any config
-// set during init.
+// Setup basic information needed to connect to balanced. Overrides any
+// previous configuration of DefaultClient.
 func SetupEnvironment(root, key, marketId string) {
-	apiRoot = root
-	apiKey = key
-	marketplaceId = marketId
+	DefaultClient.ApiRoot = root
+	DefaultClient.SetApiKey(key)
+	DefaultClient.MarketplaceId = marketId
 }
 
-// Used when running test, or when no config file was specified.
+// Configures DefaultClient against a freshly generated test api key and test
+// marketplace. Meant for running tests, DefaultClient is left untouched if an
+// error is returned.
 // The api invoked by this function is not a public endpoint at balanced.
 // May not work in the future.
-func setupTestEnvironment() {
-	apiRoot = testApiRoot
+func SetupTestEnvironment() error {
+	return setupTestEnvironment(DefaultApiRoot)
+}
+
+func setupTestEnvironment(root string) error {
+	ctx := context.Background()
+	client := NewClient(root, "", "")
 
 	// Get test api key from balanced
 	key := ApiKey{}
-	err := post(apiKeyUri, nil, &key)
+	err := client.post(ctx, apiKeyUri, nil, &key)
 	if err != nil {
-		log.Println("Unable to generate test key")
-		os.Exit(1)
+		return fmt.Errorf("Balanced API: Unable to generate test key: %w", err)
 	}
 
-	apiKey = key.Secret
+	client.SetApiKey(key.Secret)
 
 	// Get test marketplace from balanced
 	marketplace := Marketplace{}
-	err = post(marketplaceUri, nil, &marketplace)
+	err = client.post(ctx, marketplaceUri, nil, &marketplace)
 	if err != nil {
-		log.Println("Unable to generate test marketplace")
-		os.Exit(1)
+		return fmt.Errorf("Balanced API: Unable to generate test marketplace: %w", err)
 	}
 
-	marketplaceId = marketplace.Id
+	SetupEnvironment(client.ApiRoot, key.Secret, marketplace.Id)
+
+	return nil
 }
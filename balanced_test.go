@@ -0,0 +1,30 @@
+package balanced
+
+import (
+	"log"
+	"os"
+	"testing"
+
+	"github.com/nimajalali/balanced-go/balancedtest"
+)
+
+// Tests run against an in-process fake of the balanced api. Set
+// BALANCED_TEST_LIVE=1 to run them against a live test marketplace instead.
+func TestMain(m *testing.M) {
+	if len(os.Getenv("BALANCED_TEST_LIVE")) != 0 {
+		if err := SetupTestEnvironment(); err != nil {
+			log.Println(err)
+			os.Exit(1)
+		}
+
+		os.Exit(m.Run())
+	}
+
+	server := balancedtest.NewServer()
+	SetupEnvironment(server.URL, server.ApiKey, server.MarketplaceId)
+
+	code := m.Run()
+	server.Close()
+
+	os.Exit(code)
+}
@@ -0,0 +1,959 @@
+package balancedtest
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	holdLifetime = 7 * 24 * time.Hour
+)
+
+// Bank names returned for well known routing numbers.
+var bankNames = map[string]string{
+	"121000358": "BANK OF AMERICA, N.A.",
+	"021000021": "JPMORGAN CHASE BANK",
+	"121000248": "WELLS FARGO BANK",
+}
+
+func (s *Server) createApiKey(form url.Values) object {
+	id := s.newId("AK")
+	secret := "ak-test-" + strings.ToLower(s.newId(""))
+
+	merchantId := s.newId("MR")
+	key := s.store(object{
+		"_type":      "api_key",
+		"id":         id,
+		"uri":        "/v1/api_keys/" + id,
+		"created_at": now(),
+		"meta":       parseMeta(form, ""),
+		"merchant": object{
+			"_type":        "merchant",
+			"id":           merchantId,
+			"uri":          "/v1/merchants/" + merchantId,
+			"type":         "person",
+			"name":         "Test Merchant",
+			"created_at":   now(),
+			"api_keys_uri": "/v1/merchants/" + merchantId + "/api_keys",
+			"accounts_uri": "/v1/merchants/" + merchantId + "/accounts",
+			"balance":      0,
+			"meta":         object{},
+		},
+	}, "/v1/api_keys")
+	s.apiKeys[secret] = key["uri"].(string)
+
+	// The secret is only ever returned when the key is created
+	created := snapshot(key)
+	created["secret"] = secret
+
+	return created
+}
+
+func (s *Server) createMarketplace(form url.Values) object {
+	id := "TEST-" + s.newId("MP")
+	uri := "/v1/marketplaces/" + id
+
+	marketplace := object{
+		"_type":                 "marketplace",
+		"id":                    id,
+		"uri":                   uri,
+		"name":                  "Test Marketplace",
+		"support_email_address": "support@example.com",
+		"support_phone_number":  "+16505551234",
+		"domain_url":            "example.com",
+		"in_escrow":             0,
+		"meta":                  parseMeta(form, ""),
+		"accounts_uri":          uri + "/accounts",
+		"bank_accounts_uri":     uri + "/bank_accounts",
+		"callbacks_uri":         uri + "/callbacks",
+		"cards_uri":             uri + "/cards",
+		"credits_uri":           uri + "/credits",
+		"debits_uri":            uri + "/debits",
+		"events_uri":            uri + "/events",
+		"holds_uri":             uri + "/holds",
+		"refunds_uri":           uri + "/refunds",
+		"transactions_uri":      uri + "/transactions",
+	}
+	for _, key := range []string{"name", "support_email_address",
+		"support_phone_number", "domain_url"} {
+		setString(marketplace, form, key)
+	}
+	s.store(marketplace, "/v1/marketplaces")
+	s.newLists(uri+"/accounts", uri+"/bank_accounts", uri+"/callbacks",
+		uri+"/cards", uri+"/credits", uri+"/debits", uri+"/holds",
+		uri+"/refunds", uri+"/transactions")
+
+	// Every marketplace is owned by an underwritten account with a bank account
+	owner := s.newAccount(id, url.Values{"name": {"Test Marketplace Owner"}})
+	owner["roles"] = []interface{}{"merchant", "buyer"}
+
+	bankAccount, _ := s.createBankAccount(url.Values{
+		"name":           {"Test Marketplace Owner"},
+		"account_number": {"9900000002"},
+		"routing_number": {"021000021"},
+		"type":           {"checking"},
+	})
+	s.lists[owner["bank_accounts_uri"].(string)] = []string{
+		bankAccount["uri"].(string)}
+
+	marketplace["owner_account"] = snapshot(owner)
+
+	return marketplace
+}
+
+func (s *Server) updateMarketplace(marketplace object, form url.Values) *apiError {
+	for _, key := range []string{"name", "support_email_address",
+		"support_phone_number", "domain_url"} {
+		setString(marketplace, form, key)
+	}
+	updateMeta(marketplace, form)
+
+	return nil
+}
+
+// Returns the transactions lists a transaction of the marketplace, and of the
+// account if any, belongs to.
+func transactionLists(marketplaceId string, account object) []string {
+	lists := []string{"/v1/marketplaces/" + marketplaceId + "/transactions"}
+	if account != nil {
+		lists = append(lists, account["transactions_uri"].(string))
+	}
+
+	return lists
+}
+
+// Adds delta to the escrow balance of a marketplace.
+func (s *Server) adjustEscrow(marketplaceId string, delta int) {
+	marketplace := s.resources["/v1/marketplaces/"+marketplaceId]
+	if marketplace == nil {
+		return
+	}
+
+	marketplace["in_escrow"] = marketplace["in_escrow"].(int) + delta
+}
+
+// Registers a callback url for the events of a marketplace.
+func (s *Server) createCallback(marketplaceId string, form url.Values) (object, *apiError) {
+	callbackUrl := form.Get("url")
+	if len(callbackUrl) == 0 {
+		return nil, badRequest("Missing required field [url]")
+	}
+
+	parsed, err := url.Parse(callbackUrl)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") ||
+		len(parsed.Host) == 0 {
+		return nil, badRequest("Invalid field [url] - %q is not a valid url",
+			callbackUrl)
+	}
+
+	method := form.Get("method")
+	if len(method) == 0 {
+		method = "post"
+	}
+
+	id := s.newId("CB")
+	uri := "/v1/marketplaces/" + marketplaceId + "/callbacks"
+
+	return s.store(object{
+		"_type":      "callback",
+		"id":         id,
+		"uri":        uri + "/" + id,
+		"url":        callbackUrl,
+		"method":     method,
+		"created_at": now(),
+	}, uri), nil
+}
+
+// Creates and stores an account, without any validation.
+func (s *Server) newAccount(marketplaceId string, form url.Values) object {
+	id := s.newId("AC")
+	uri := "/v1/marketplaces/" + marketplaceId + "/accounts/" + id
+
+	account := s.store(object{
+		"_type":             "account",
+		"id":                id,
+		"uri":               uri,
+		"name":              optional(form, "name"),
+		"email_address":     optional(form, "email_address"),
+		"roles":             []interface{}{},
+		"meta":              parseMeta(form, ""),
+		"created_at":        now(),
+		"bank_accounts_uri": uri + "/bank_accounts",
+		"cards_uri":         uri + "/cards",
+		"credits_uri":       uri + "/credits",
+		"debits_uri":        uri + "/debits",
+		"holds_uri":         uri + "/holds",
+		"refunds_uri":       uri + "/refunds",
+		"transactions_uri":  uri + "/transactions",
+	}, "/v1/marketplaces/"+marketplaceId+"/accounts")
+	s.newLists(uri+"/bank_accounts", uri+"/cards", uri+"/credits",
+		uri+"/debits", uri+"/holds", uri+"/refunds", uri+"/transactions")
+
+	return account
+}
+
+func (s *Server) createAccount(marketplaceId string, form url.Values) (object, *apiError) {
+	if err := s.checkEmailAddress(marketplaceId, nil, form); err != nil {
+		return nil, err
+	}
+	if err := checkMerchant(form); err != nil {
+		return nil, err
+	}
+
+	account := s.newAccount(marketplaceId, form)
+	if err := s.updateAccount(account, form); err != nil {
+		s.remove(account["uri"].(string))
+		return nil, err
+	}
+	s.emit("account.created", account)
+
+	return account, nil
+}
+
+func (s *Server) updateAccount(account object, form url.Values) *apiError {
+	marketplaceId := strings.Split(account["uri"].(string), "/")[3]
+	if err := s.checkEmailAddress(marketplaceId, account, form); err != nil {
+		return err
+	}
+	if err := checkMerchant(form); err != nil {
+		return err
+	}
+
+	if cardUri := form.Get("card_uri"); len(cardUri) != 0 {
+		card, err := s.lookup(cardUri, "card", "card_uri")
+		if err != nil {
+			return err
+		}
+
+		addRole(account, "buyer")
+		card["account"] = snapshot(account)
+		s.lists[account["cards_uri"].(string)] = append(
+			s.lists[account["cards_uri"].(string)], cardUri)
+	}
+
+	if bankAccountUri := form.Get("bank_account_uri"); len(bankAccountUri) != 0 {
+		if _, err := s.lookup(bankAccountUri, "bank_account", "bank_account_uri"); err != nil {
+			return err
+		}
+
+		s.lists[account["bank_accounts_uri"].(string)] = append(
+			s.lists[account["bank_accounts_uri"].(string)], bankAccountUri)
+	}
+
+	if len(form.Get("merchant[type]")) != 0 {
+		addRole(account, "merchant")
+	}
+
+	setString(account, form, "name")
+	setString(account, form, "email_address")
+	updateMeta(account, form)
+
+	return nil
+}
+
+// Rejects email addresses already used by another account of the marketplace.
+func (s *Server) checkEmailAddress(marketplaceId string, account object, form url.Values) *apiError {
+	email := form.Get("email_address")
+	if len(email) == 0 {
+		return nil
+	}
+
+	for _, uri := range s.lists["/v1/marketplaces/"+marketplaceId+"/accounts"] {
+		other := s.resources[uri]
+		if other["email_address"] == email && (account == nil ||
+			other["uri"] != account["uri"]) {
+			return conflict("duplicate-email-address",
+				"Account with email address %v already exists", email)
+		}
+	}
+
+	return nil
+}
+
+// Checks the required fields for underwriting a merchant, if present.
+func checkMerchant(form url.Values) *apiError {
+	merchantType := form.Get("merchant[type]")
+	if len(merchantType) == 0 {
+		return nil
+	}
+
+	required := []string{"phone_number", "name", "postal_code", "street_address"}
+	switch merchantType {
+	case "person":
+		required = append(required, "dob")
+	case "business":
+		required = append(required, "tax_id", "person[name]", "person[dob]",
+			"person[postal_code]", "person[street_address]")
+	default:
+		return badRequest("Invalid field [merchant[type]] - %q must be person "+
+			"or business", merchantType)
+	}
+
+	for _, field := range required {
+		if len(form.Get("merchant["+field+"]")) == 0 {
+			return badRequest("Missing required field [merchant[%v]]", field)
+		}
+	}
+
+	return nil
+}
+
+func addRole(account object, role string) {
+	roles, _ := account["roles"].([]interface{})
+	for _, existing := range roles {
+		if existing == role {
+			return
+		}
+	}
+
+	account["roles"] = append(roles, role)
+}
+
+func (s *Server) createCard(marketplaceId string, form url.Values) (object, *apiError) {
+	number := form.Get("card_number")
+	if len(number) == 0 {
+		return nil, badRequest("Missing required field [card_number]")
+	}
+	if !luhnValid(number) {
+		return nil, badRequest("Invalid field [card_number] - %q is not a valid "+
+			"credit card number", number)
+	}
+
+	month, err := strconv.Atoi(form.Get("expiration_month"))
+	if err != nil || month < 1 || month > 12 {
+		return nil, badRequest("Invalid field [expiration_month] - %q must be "+
+			"a month between 1 and 12", form.Get("expiration_month"))
+	}
+	year, err := strconv.Atoi(form.Get("expiration_year"))
+	if err != nil {
+		return nil, badRequest("Invalid field [expiration_year] - %q must be "+
+			"a year", form.Get("expiration_year"))
+	}
+
+	brand := cardBrand(number)
+	id := s.newId("CC")
+	uri := "/v1/marketplaces/" + marketplaceId + "/cards/" + id
+
+	card := s.store(object{
+		"_type":            "card",
+		"id":               id,
+		"uri":              uri,
+		"brand":            brand,
+		"card_type":        strings.ToLower(strings.ReplaceAll(brand, " ", "_")),
+		"card_number":      strings.Repeat("x", len(number)-4) + number[len(number)-4:],
+		"last_four":        number[len(number)-4:],
+		"expiration_month": month,
+		"expiration_year":  year,
+		"name":             optional(form, "name"),
+		"phone_number":     optional(form, "phone_number"),
+		"street_address":   optional(form, "street_address"),
+		"city":             optional(form, "city"),
+		"postal_code":      optional(form, "postal_code"),
+		"country_code":     optional(form, "country_code"),
+		"hash":             fingerprint(number),
+		"is_valid":         true,
+		"can_debit":        true,
+		"account":          nil,
+		"meta":             parseMeta(form, ""),
+		"created_at":       now(),
+	}, "/v1/marketplaces/"+marketplaceId+"/cards")
+	s.cardNumbers[uri] = number
+	s.emit("card.created", card)
+
+	return card, nil
+}
+
+func (s *Server) updateCard(card object, form url.Values) *apiError {
+	if form.Get("is_valid") == "false" {
+		card["is_valid"] = false
+		card["can_debit"] = false
+	}
+	updateMeta(card, form)
+
+	return nil
+}
+
+// Returns the card brand for a card number.
+func cardBrand(number string) string {
+	switch {
+	case strings.HasPrefix(number, "4"):
+		return "Visa"
+	case strings.HasPrefix(number, "34"), strings.HasPrefix(number, "37"):
+		return "American Express"
+	case len(number) > 1 && number[0] == '5' && number[1] >= '1' && number[1] <= '5':
+		return "MasterCard"
+	case strings.HasPrefix(number, "6011"), strings.HasPrefix(number, "65"):
+		return "Discover"
+	}
+
+	return "Unknown"
+}
+
+// Reports whether a card number passes the Luhn checksum.
+func luhnValid(number string) bool {
+	if len(number) < 12 {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		digit := int(number[i] - '0')
+		if digit < 0 || digit > 9 {
+			return false
+		}
+
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+
+	return sum%10 == 0
+}
+
+func fingerprint(values ...string) string {
+	return fmt.Sprintf("%x", sha1.Sum([]byte(strings.Join(values, ":"))))
+}
+
+// Builds a bank account from form fields prefixed with prefix, without storing
+// it.
+func (s *Server) newBankAccount(form url.Values, prefix string) (object, *apiError) {
+	field := func(name string) string {
+		if len(prefix) == 0 {
+			return name
+		}
+		return prefix + "[" + name + "]"
+	}
+
+	for _, name := range []string{"name", "account_number", "routing_number", "type"} {
+		if len(form.Get(field(name))) == 0 {
+			return nil, badRequest("Missing required field [%v]", field(name))
+		}
+	}
+
+	accountType := form.Get(field("type"))
+	if accountType != "checking" && accountType != "savings" {
+		return nil, badRequest("Invalid field [%v] - %q must be checking or "+
+			"savings", field("type"), accountType)
+	}
+
+	routingNumber := form.Get(field("routing_number"))
+	if !routingNumberValid(routingNumber) {
+		return nil, badRequest("Invalid field [%v] - %q is not a valid routing "+
+			"number", field("routing_number"), routingNumber)
+	}
+
+	accountNumber := form.Get(field("account_number"))
+	id := s.newId("BA")
+	uri := "/v1/bank_accounts/" + id
+	masked := accountNumber
+	if len(masked) > 4 {
+		masked = strings.Repeat("x", len(masked)-4) + masked[len(masked)-4:]
+	}
+
+	return object{
+		"_type":             "bank_account",
+		"id":                id,
+		"uri":               uri,
+		"name":              form.Get(field("name")),
+		"account_number":    masked,
+		"routing_number":    routingNumber,
+		"type":              accountType,
+		"bank_name":         bankNames[routingNumber],
+		"can_debit":         false,
+		"fingerprint":       fingerprint(routingNumber, accountNumber),
+		"credits_uri":       uri + "/credits",
+		"debits_uri":        uri + "/debits",
+		"verifications_uri": uri + "/verifications",
+		"verification_uri":  nil,
+		"meta":              parseMeta(form, prefix),
+		"created_at":        now(),
+	}, nil
+}
+
+func (s *Server) createBankAccount(form url.Values) (object, *apiError) {
+	bankAccount, err := s.newBankAccount(form, "")
+	if err != nil {
+		return nil, err
+	}
+
+	uri := bankAccount["uri"].(string)
+	s.store(bankAccount, "/v1/bank_accounts")
+	s.newLists(uri+"/credits", uri+"/debits", uri+"/verifications")
+	s.emit("bank_account.created", bankAccount)
+
+	return bankAccount, nil
+}
+
+func (s *Server) updateBankAccount(bankAccount object, form url.Values) *apiError {
+	updateMeta(bankAccount, form)
+
+	return nil
+}
+
+// Reports whether a routing number passes the ABA checksum.
+func routingNumberValid(number string) bool {
+	if len(number) != 9 {
+		return false
+	}
+
+	weights := []int{3, 7, 1, 3, 7, 1, 3, 7, 1}
+	sum := 0
+	for i := range number {
+		if number[i] < '0' || number[i] > '9' {
+			return false
+		}
+		sum += int(number[i]-'0') * weights[i]
+	}
+
+	return sum%10 == 0
+}
+
+func (s *Server) createVerification(bankAccount object) (object, *apiError) {
+	if bankAccount == nil {
+		return nil, badRequest("Invalid bank account")
+	}
+
+	id := s.newId("BZ")
+	verificationsUri := bankAccount["verifications_uri"].(string)
+	verification := s.store(object{
+		"_type":              "bank_account_authentication",
+		"id":                 id,
+		"uri":                verificationsUri + "/" + id,
+		"attempts":           0,
+		"remaining_attempts": 3,
+		"state":              "pending",
+	}, verificationsUri)
+	bankAccount["verification_uri"] = verification["uri"]
+
+	return verification, nil
+}
+
+// Confirms the trial deposit amounts, which are always 1 and 1.
+func (s *Server) confirmVerification(verification object, form url.Values) *apiError {
+	if verification["state"] != "pending" {
+		return conflict("bank-account-authentication-already-complete",
+			"Bank account verification is already %v", verification["state"])
+	}
+
+	bankAccount := s.resources[parent(parent(verification["uri"].(string)))]
+
+	verification["attempts"] = verification["attempts"].(int) + 1
+	if form.Get("amount_1") == "1" && form.Get("amount_2") == "1" {
+		verification["state"] = "verified"
+		bankAccount["can_debit"] = true
+		return nil
+	}
+
+	verification["remaining_attempts"] = verification["remaining_attempts"].(int) - 1
+	if verification["remaining_attempts"] == 0 {
+		verification["state"] = "failed"
+	}
+
+	return conflict("bank-account-authentication-failed",
+		"Trial deposit amounts do not match")
+}
+
+// Finds the funding source of a debit or hold: the source_uri or card_uri
+// parameter, or the most recent card of the account.
+func (s *Server) fundingSource(account object, form url.Values) (object, *apiError) {
+	for _, param := range []string{"source_uri", "card_uri"} {
+		uri := form.Get(param)
+		if len(uri) == 0 {
+			continue
+		}
+
+		source, ok := s.resources[uri]
+		if !ok || (source["_type"] != "card" && source["_type"] != "bank_account") {
+			return nil, badRequest("Invalid field [%v] - %v is not a valid "+
+				"funding source uri", param, uri)
+		}
+
+		return source, nil
+	}
+
+	if account != nil {
+		cards := s.lists[account["cards_uri"].(string)]
+		if len(cards) != 0 {
+			return s.resources[cards[len(cards)-1]], nil
+		}
+	}
+
+	return nil, conflict("no-funding-source", "No funding source was given "+
+		"and the account has no card")
+}
+
+// Checks that a funding source can be charged.
+func (s *Server) checkSource(source object) *apiError {
+	if source["_type"] == "bank_account" {
+		if source["can_debit"] != true {
+			return conflict("bank-account-not-verified",
+				"Bank account %v must be verified before it can be debited",
+				source["id"])
+		}
+		return nil
+	}
+
+	if source["is_valid"] != true {
+		return conflict("card-not-valid", "Card %v has been invalidated",
+			source["id"])
+	}
+
+	switch s.cardNumbers[source["uri"].(string)] {
+	case DeclinedCardNumber:
+		return paymentRequired("card-declined", "Card %v was declined by "+
+			"the processor", source["id"])
+	case InsufficientFundsCardNumber:
+		return paymentRequired("insufficient-funds", "Card %v has "+
+			"insufficient funds", source["id"])
+	}
+
+	return nil
+}
+
+// Returns the account a funding source belongs to, if any.
+func (s *Server) sourceAccount(source object) object {
+	if account, ok := source["account"].(object); ok {
+		return s.resources[account["uri"].(string)]
+	}
+
+	return nil
+}
+
+func (s *Server) createHold(marketplaceId string, account object, form url.Values) (object, *apiError) {
+	amount, err := parseAmount(form, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if account == nil && len(form.Get("account_uri")) != 0 {
+		if account, err = s.lookup(form.Get("account_uri"), "account", "account_uri"); err != nil {
+			return nil, err
+		}
+	}
+
+	source, err := s.fundingSource(account, form)
+	if err != nil {
+		return nil, err
+	}
+	if source["_type"] != "card" {
+		return nil, badRequest("Holds can only be created against cards")
+	}
+	if err := s.checkSource(source); err != nil {
+		return nil, err
+	}
+	if account == nil {
+		account = s.sourceAccount(source)
+	}
+
+	hold := s.newHold(marketplaceId, account, source, amount, form)
+	s.emit("hold.created", hold)
+
+	return hold, nil
+}
+
+// Creates and stores a hold, without any validation.
+func (s *Server) newHold(marketplaceId string, account, source object, amount int, form url.Values) object {
+	id := s.newId("HL")
+	created := time.Now().UTC()
+
+	lists := []string{"/v1/marketplaces/" + marketplaceId + "/holds"}
+	if account != nil {
+		lists = append(lists, account["holds_uri"].(string))
+	}
+	lists = append(lists, transactionLists(marketplaceId, account)...)
+
+	return s.store(object{
+		"_type":                   "hold",
+		"id":                      id,
+		"uri":                     "/v1/marketplaces/" + marketplaceId + "/holds/" + id,
+		"amount":                  amount,
+		"description":             optional(form, "description"),
+		"appears_on_statement_as": optional(form, "appears_on_statement_as"),
+		"account":                 snapshot(account),
+		"source":                  snapshot(source),
+		"is_void":                 false,
+		"debit":                   nil,
+		"fee":                     nil,
+		"transaction_number":      s.transactionNumber("HL"),
+		"meta":                    parseMeta(form, ""),
+		"created_at":              created.Format(time.RFC3339Nano),
+		"expires_at":              created.Add(holdLifetime).Format(time.RFC3339Nano),
+	}, lists...)
+}
+
+func (s *Server) updateHold(hold object, form url.Values) *apiError {
+	if form.Get("is_void") == "true" && hold["is_void"] != true {
+		if hold["debit"] != nil {
+			return conflict("hold-already-captured",
+				"Hold %v has already been captured", hold["id"])
+		}
+
+		hold["is_void"] = true
+		s.emit("hold.updated", hold)
+	}
+
+	setString(hold, form, "description")
+	setString(hold, form, "appears_on_statement_as")
+	updateMeta(hold, form)
+
+	return nil
+}
+
+func (s *Server) createDebit(marketplaceId string, account object, form url.Values) (object, *apiError) {
+	amount, err := parseAmount(form, len(form.Get("hold_uri")) == 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if account == nil && len(form.Get("account_uri")) != 0 {
+		if account, err = s.lookup(form.Get("account_uri"), "account", "account_uri"); err != nil {
+			return nil, err
+		}
+	}
+
+	var hold, source object
+	if holdUri := form.Get("hold_uri"); len(holdUri) != 0 {
+		// Capture an existing hold
+		if hold, err = s.lookup(holdUri, "hold", "hold_uri"); err != nil {
+			return nil, err
+		}
+		if hold["is_void"] == true {
+			return nil, conflict("hold-void", "Hold %v has been voided",
+				hold["id"])
+		}
+		if hold["debit"] != nil {
+			return nil, conflict("hold-already-captured",
+				"Hold %v has already been captured", hold["id"])
+		}
+
+		holdAmount := hold["amount"].(int)
+		if amount == 0 {
+			amount = holdAmount
+		}
+		if amount > holdAmount {
+			return nil, badRequest("Invalid field [amount] - %v exceeds the "+
+				"hold amount of %v", amount, holdAmount)
+		}
+
+		source = s.resources[hold["source"].(object)["uri"].(string)]
+		if account == nil {
+			if holdAccount, ok := hold["account"].(object); ok {
+				account = s.resources[holdAccount["uri"].(string)]
+			}
+		}
+	} else {
+		if source, err = s.fundingSource(account, form); err != nil {
+			return nil, err
+		}
+		if err := s.checkSource(source); err != nil {
+			return nil, err
+		}
+		if account == nil {
+			account = s.sourceAccount(source)
+		}
+
+		// Card debits are backed by a hold that is captured right away
+		if source["_type"] == "card" {
+			hold = s.newHold(marketplaceId, account, source, amount, url.Values{})
+		}
+	}
+
+	// Cards settle right away, ACH debits are pending until the next day
+	status := "succeeded"
+	if source["_type"] == "bank_account" {
+		status = "pending"
+	}
+
+	id := s.newId("WD")
+	uri := "/v1/marketplaces/" + marketplaceId + "/debits/" + id
+	created := now()
+
+	lists := []string{"/v1/marketplaces/" + marketplaceId + "/debits"}
+	if account != nil {
+		lists = append(lists, account["debits_uri"].(string))
+	}
+	lists = append(lists, transactionLists(marketplaceId, account)...)
+
+	debit := s.store(object{
+		"_type":                   "debit",
+		"id":                      id,
+		"uri":                     uri,
+		"amount":                  amount,
+		"description":             optional(form, "description"),
+		"appears_on_statement_as": optional(form, "appears_on_statement_as"),
+		"on_behalf_of":            optional(form, "on_behalf_of_uri"),
+		"status":                  status,
+		"account":                 snapshot(account),
+		"source":                  snapshot(source),
+		"hold":                    snapshot(hold),
+		"fee":                     nil,
+		"refunds_uri":             uri + "/refunds",
+		"transaction_number":      s.transactionNumber("W"),
+		"meta":                    parseMeta(form, ""),
+		"created_at":              created,
+		"available_at":            created,
+	}, lists...)
+	s.newLists(uri + "/refunds")
+
+	if hold != nil {
+		hold["debit"] = snapshot(debit)
+	}
+
+	s.emit("debit.created", debit)
+	if status == "succeeded" {
+		s.adjustEscrow(marketplaceId, amount)
+		s.emit("debit.succeeded", debit)
+	}
+
+	return debit, nil
+}
+
+func (s *Server) createRefund(debit object, form url.Values) (object, *apiError) {
+	if debit == nil {
+		return nil, badRequest("Invalid debit")
+	}
+
+	refundsUri := debit["refunds_uri"].(string)
+	refunded := 0
+	for _, uri := range s.lists[refundsUri] {
+		refunded += s.resources[uri]["amount"].(int)
+	}
+	remaining := debit["amount"].(int) - refunded
+
+	amount, err := parseAmount(form, false)
+	if err != nil {
+		return nil, err
+	}
+	if amount == 0 {
+		amount = remaining
+	}
+	if amount > remaining || remaining == 0 {
+		return nil, badRequest("Invalid field [amount] - %v exceeds the "+
+			"refundable amount of %v", amount, remaining)
+	}
+
+	marketplaceId := strings.Split(debit["uri"].(string), "/")[3]
+	id := s.newId("RF")
+
+	lists := []string{"/v1/marketplaces/" + marketplaceId + "/refunds", refundsUri}
+	account, _ := debit["account"].(object)
+	if account != nil {
+		lists = append(lists, account["refunds_uri"].(string))
+	}
+	lists = append(lists, transactionLists(marketplaceId, account)...)
+
+	refund := s.store(object{
+		"_type":                   "refund",
+		"id":                      id,
+		"uri":                     "/v1/marketplaces/" + marketplaceId + "/refunds/" + id,
+		"amount":                  amount,
+		"description":             optional(form, "description"),
+		"appears_on_statement_as": debit["appears_on_statement_as"],
+		"debit":                   snapshot(debit),
+		"account":                 account,
+		"fee":                     nil,
+		"transaction_number":      s.transactionNumber("RF"),
+		"meta":                    parseMeta(form, ""),
+		"created_at":              now(),
+	}, lists...)
+
+	s.adjustEscrow(marketplaceId, -amount)
+	s.emit("refund.created", refund)
+
+	return refund, nil
+}
+
+// Credits the bank account of an account, given by destination_uri or
+// bank_account_uri, or its most recent bank account.
+func (s *Server) createAccountCredit(marketplaceId string, account object, form url.Values) (object, *apiError) {
+	var bankAccount object
+	for _, param := range []string{"destination_uri", "bank_account_uri"} {
+		if uri := form.Get(param); len(uri) != 0 {
+			var err *apiError
+			if bankAccount, err = s.lookup(uri, "bank_account", param); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	if bankAccount == nil {
+		bankAccounts := s.lists[account["bank_accounts_uri"].(string)]
+		if len(bankAccounts) == 0 {
+			return nil, conflict("no-funding-destination", "No funding "+
+				"destination was given and the account has no bank account")
+		}
+		bankAccount = s.resources[bankAccounts[len(bankAccounts)-1]]
+	}
+
+	return s.createCredit(bankAccount, account, form)
+}
+
+// Credits a new bank account given by bank_account[...] parameters. The bank
+// account is not stored.
+func (s *Server) createNewBankAccountCredit(form url.Values) (object, *apiError) {
+	bankAccount, err := s.newBankAccount(form, "bank_account")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.createCredit(bankAccount, nil, form)
+}
+
+func (s *Server) createCredit(bankAccount, account object, form url.Values) (object, *apiError) {
+	if bankAccount == nil {
+		return nil, badRequest("Invalid bank account")
+	}
+
+	amount, err := parseAmount(form, true)
+	if err != nil {
+		return nil, err
+	}
+
+	id := s.newId("CR")
+	created := now()
+
+	lists := []string{"/v1/credits",
+		"/v1/marketplaces/" + s.MarketplaceId + "/credits"}
+	if _, stored := s.resources[bankAccount["uri"].(string)]; stored {
+		lists = append(lists, bankAccount["credits_uri"].(string))
+	}
+	if account != nil {
+		lists = append(lists, account["credits_uri"].(string))
+	}
+	lists = append(lists, transactionLists(s.MarketplaceId, account)...)
+
+	credit := s.store(object{
+		"_type":                   "credit",
+		"id":                      id,
+		"uri":                     "/v1/credits/" + id,
+		"amount":                  amount,
+		"description":             optional(form, "description"),
+		"appears_on_statement_as": optional(form, "appears_on_statement_as"),
+		"status":                  "pending",
+		"is_void":                 false,
+		"account":                 snapshot(account),
+		"bank_account":            snapshot(bankAccount),
+		"destination":             snapshot(bankAccount),
+		"fee":                     nil,
+		"transaction_number":      s.transactionNumber("CR"),
+		"meta":                    parseMeta(form, ""),
+		"created_at":              created,
+		"available_at":            created,
+	}, lists...)
+
+	s.adjustEscrow(s.MarketplaceId, -amount)
+	s.emit("credit.created", credit)
+
+	return credit, nil
+}
@@ -0,0 +1,727 @@
+// Package balancedtest provides an in-process fake of the Balanced REST API for
+// tests that must run without network access.
+//
+// The fake keeps all state in memory and answers with the same json and uri
+// shapes as balanced, so the bindings decode its responses like real ones:
+//
+//	server := balancedtest.NewServer()
+//	defer server.Close()
+//
+//	client := balanced.NewClient(server.URL, server.ApiKey, server.MarketplaceId)
+//
+// Test card numbers behave like they do on a balanced test marketplace:
+// 4222222222222220 is declined and 4444444444444448 has insufficient funds.
+package balancedtest
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Card numbers with special behavior, as on a balanced test marketplace.
+	DeclinedCardNumber          = "4222222222222220"
+	InsufficientFundsCardNumber = "4444444444444448"
+
+	defaultLimit = 10
+	eventsUri    = "/v1/events"
+)
+
+type object = map[string]interface{}
+
+// A fake balanced api server. All exported methods are safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	// Secret of the api key created along with the server.
+	ApiKey string
+	// Id of the marketplace created along with the server.
+	MarketplaceId string
+
+	mu sync.Mutex
+	// Every resource by uri
+	resources map[string]object
+	// Item uris of every list resource by uri, oldest first
+	lists map[string][]string
+	// Api key uris by secret
+	apiKeys map[string]string
+	// Full card numbers by card uri
+	cardNumbers map[string]string
+	counter     int
+}
+
+// Starts a new fake server with an api key and a marketplace. The marketplace
+// owner account comes with a bank account and a card, like on balanced.
+// The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		resources:   map[string]object{},
+		lists:       map[string][]string{eventsUri: nil},
+		apiKeys:     map[string]string{},
+		cardNumbers: map[string]string{},
+	}
+	s.Server = httptest.NewServer(s)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := s.createApiKey(url.Values{})
+	s.ApiKey = key["secret"].(string)
+
+	marketplace := s.createMarketplace(url.Values{})
+	s.MarketplaceId = marketplace["id"].(string)
+
+	return s
+}
+
+// Settles the pending ACH debit or credit at uri with the given status, as
+// balanced does once the transfer clears or is returned: "succeeded" or
+// "failed" for debits, and "succeeded", "paid" or "failed" for credits.
+func (s *Server) Settle(uri, status string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resource, ok := s.resources[uri]
+	if !ok || (resource["_type"] != "debit" && resource["_type"] != "credit") {
+		return fmt.Errorf("balancedtest: %v is not a debit or credit", uri)
+	}
+	if resource["status"] != "pending" {
+		return fmt.Errorf("balancedtest: %v is already %v", uri,
+			resource["status"])
+	}
+
+	resourceType := resource["_type"].(string)
+	amount := resource["amount"].(int)
+
+	switch {
+	case resourceType == "debit" && status == "succeeded":
+		// Debits are listed under /v1/marketplaces/MP.../debits
+		s.adjustEscrow(strings.Split(uri, "/")[3], amount)
+	case resourceType == "credit" && (status == "succeeded" || status == "paid"):
+	case status == "failed":
+		if resourceType == "credit" {
+			s.adjustEscrow(s.MarketplaceId, amount)
+		}
+	default:
+		return fmt.Errorf("balancedtest: Invalid %v status %v", resourceType,
+			status)
+	}
+
+	resource["status"] = status
+	if status == "paid" {
+		status = "succeeded"
+	}
+	s.emit(resourceType+"."+status, resource)
+
+	return nil
+}
+
+// An error response, in the shape balanced uses.
+type apiError struct {
+	StatusCode   int
+	CategoryType string
+	CategoryCode string
+	Description  string
+}
+
+func (e *apiError) Error() string {
+	return e.Description
+}
+
+func notFound(path string) *apiError {
+	return &apiError{http.StatusNotFound, "request", "not-found",
+		fmt.Sprintf("The requested URL %v was not found on this server.", path)}
+}
+
+func badRequest(format string, args ...interface{}) *apiError {
+	return &apiError{http.StatusBadRequest, "request", "request",
+		fmt.Sprintf(format, args...)}
+}
+
+func conflict(code, format string, args ...interface{}) *apiError {
+	return &apiError{http.StatusConflict, "logical", code,
+		fmt.Sprintf(format, args...)}
+}
+
+func paymentRequired(code, format string, args ...interface{}) *apiError {
+	return &apiError{http.StatusPaymentRequired, "banking", code,
+		fmt.Sprintf(format, args...)}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status, body, err := s.serve(r)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if body != nil {
+		json.NewEncoder(w).Encode(body)
+	}
+}
+
+func (s *Server) writeError(w http.ResponseWriter, err *apiError) {
+	s.counter++
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(err.StatusCode)
+	json.NewEncoder(w).Encode(object{
+		"status":        http.StatusText(err.StatusCode),
+		"status_code":   err.StatusCode,
+		"category_type": err.CategoryType,
+		"category_code": err.CategoryCode,
+		"description":   err.Description,
+		"request_id":    fmt.Sprintf("OHM%022d", s.counter),
+		"extras":        object{},
+	})
+}
+
+func (s *Server) serve(r *http.Request) (int, interface{}, *apiError) {
+	if err := r.ParseForm(); err != nil {
+		return 0, nil, badRequest("Invalid request body: %v", err)
+	}
+
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	if !strings.HasPrefix(path, "/v1/") {
+		return 0, nil, notFound(path)
+	}
+	seg := strings.Split(strings.TrimPrefix(path, "/v1/"), "/")
+
+	// Generating a test api key is the only unauthenticated request
+	if !(r.Method == "POST" && path == "/v1/api_keys") {
+		secret, _, _ := r.BasicAuth()
+		if _, ok := s.apiKeys[secret]; !ok {
+			return 0, nil, &apiError{http.StatusUnauthorized, "request",
+				"authentication-required", "Not permitted to perform this request."}
+		}
+	}
+
+	// Every marketplace scoped uri must name an existing marketplace
+	if len(seg) > 1 && seg[0] == "marketplaces" {
+		if _, ok := s.resources["/v1/marketplaces/"+seg[1]]; !ok {
+			return 0, nil, notFound(path)
+		}
+	}
+
+	switch r.Method {
+	case "GET":
+		if resource, ok := s.resources[path]; ok {
+			return http.StatusOK, resource, nil
+		}
+		if _, ok := s.lists[path]; ok {
+			return http.StatusOK, s.page(path, r.Form), nil
+		}
+		return 0, nil, notFound(path)
+
+	case "POST":
+		resource, err := s.create(path, seg, r.Form)
+		if err != nil {
+			return 0, nil, err
+		}
+		return http.StatusCreated, resource, nil
+
+	case "PUT":
+		resource, ok := s.resources[path]
+		if !ok {
+			return 0, nil, notFound(path)
+		}
+		if err := s.update(resource, r.Form); err != nil {
+			return 0, nil, err
+		}
+		return http.StatusOK, resource, nil
+
+	case "DELETE":
+		resource, ok := s.resources[path]
+		if !ok {
+			return 0, nil, notFound(path)
+		}
+		if err := s.delete(resource); err != nil {
+			return 0, nil, err
+		}
+		return http.StatusNoContent, nil, nil
+	}
+
+	return 0, nil, &apiError{http.StatusMethodNotAllowed, "request",
+		"method-not-allowed", "The method is not allowed for the requested URL."}
+}
+
+// Reports whether seg matches pattern, where "*" matches any segment.
+func match(seg []string, pattern ...string) bool {
+	if len(seg) != len(pattern) {
+		return false
+	}
+
+	for i := range seg {
+		if pattern[i] != "*" && pattern[i] != seg[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Handles a POST to path.
+func (s *Server) create(path string, seg []string, form url.Values) (object, *apiError) {
+	switch {
+	case match(seg, "api_keys"):
+		return s.createApiKey(form), nil
+	case match(seg, "marketplaces"):
+		return s.createMarketplace(form), nil
+	case match(seg, "marketplaces", "*", "accounts"):
+		return s.createAccount(seg[1], form)
+	case match(seg, "marketplaces", "*", "callbacks"):
+		return s.createCallback(seg[1], form)
+	case match(seg, "marketplaces", "*", "cards"):
+		return s.createCard(seg[1], form)
+	case match(seg, "marketplaces", "*", "debits"):
+		return s.createDebit(seg[1], nil, form)
+	case match(seg, "marketplaces", "*", "accounts", "*", "debits"):
+		return s.createDebit(seg[1], s.resources[parent(path)], form)
+	case match(seg, "marketplaces", "*", "holds"):
+		return s.createHold(seg[1], nil, form)
+	case match(seg, "marketplaces", "*", "accounts", "*", "holds"):
+		return s.createHold(seg[1], s.resources[parent(path)], form)
+	case match(seg, "marketplaces", "*", "refunds"):
+		debit, err := s.lookup(form.Get("debit_uri"), "debit", "debit_uri")
+		if err != nil {
+			return nil, err
+		}
+		return s.createRefund(debit, form)
+	case match(seg, "marketplaces", "*", "debits", "*", "refunds"):
+		return s.createRefund(s.resources[parent(path)], form)
+	case match(seg, "marketplaces", "*", "accounts", "*", "credits"):
+		return s.createAccountCredit(seg[1], s.resources[parent(path)], form)
+	case match(seg, "bank_accounts"):
+		return s.createBankAccount(form)
+	case match(seg, "bank_accounts", "*", "verifications"):
+		return s.createVerification(s.resources[parent(path)])
+	case match(seg, "bank_accounts", "*", "credits"):
+		return s.createCredit(s.resources[parent(path)], nil, form)
+	case match(seg, "credits"):
+		return s.createNewBankAccountCredit(form)
+	}
+
+	return nil, notFound(path)
+}
+
+// Handles a PUT to a resource.
+func (s *Server) update(resource object, form url.Values) *apiError {
+	switch resource["_type"] {
+	case "marketplace":
+		return s.updateMarketplace(resource, form)
+	case "account":
+		return s.updateAccount(resource, form)
+	case "card":
+		return s.updateCard(resource, form)
+	case "bank_account":
+		return s.updateBankAccount(resource, form)
+	case "bank_account_authentication":
+		return s.confirmVerification(resource, form)
+	case "debit", "credit", "refund":
+		setString(resource, form, "description")
+		updateMeta(resource, form)
+		return nil
+	case "hold":
+		return s.updateHold(resource, form)
+	}
+
+	return &apiError{http.StatusMethodNotAllowed, "request",
+		"method-not-allowed", "The method is not allowed for the requested URL."}
+}
+
+// Handles a DELETE of a resource.
+func (s *Server) delete(resource object) *apiError {
+	switch resource["_type"] {
+	case "bank_account":
+		s.remove(resource["uri"].(string))
+		s.emit("bank_account.deleted", resource)
+		return nil
+	case "callback":
+		s.remove(resource["uri"].(string))
+		return nil
+	case "api_key":
+		for secret, uri := range s.apiKeys {
+			if uri == resource["uri"] {
+				delete(s.apiKeys, secret)
+			}
+		}
+		s.remove(resource["uri"].(string))
+		return nil
+	}
+
+	return &apiError{http.StatusMethodNotAllowed, "request",
+		"method-not-allowed", "The method is not allowed for the requested URL."}
+}
+
+// Returns a page of the list resource at path.
+func (s *Server) page(path string, form url.Values) object {
+	limit, err := strconv.Atoi(form.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultLimit
+	}
+	offset, err := strconv.Atoi(form.Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	// Any other parameter filters the list, i.e. ?email_address=... or
+	// ?amount[>]=1000, except sort, i.e. ?sort=amount,desc
+	filters := url.Values{}
+	for key, values := range form {
+		if key != "limit" && key != "offset" && key != "sort" {
+			filters[key] = values
+		}
+	}
+
+	// Most recent first
+	uris := s.lists[path]
+	all := make([]object, 0, len(uris))
+	for i := len(uris) - 1; i >= 0; i-- {
+		if resource := s.resources[uris[i]]; matches(resource, filters) {
+			all = append(all, resource)
+		}
+	}
+
+	sortResources(all, form["sort"])
+
+	items := []object{}
+	for i := offset; i < offset+limit && i < len(all); i++ {
+		items = append(items, all[i])
+	}
+
+	pageUri := func(offset int) string {
+		query := url.Values{}
+		for key, values := range form {
+			query[key] = values
+		}
+		query.Set("limit", strconv.Itoa(limit))
+		query.Set("offset", strconv.Itoa(offset))
+
+		return path + "?" + query.Encode()
+	}
+
+	last := 0
+	if len(all) > 0 {
+		last = (len(all) - 1) / limit * limit
+	}
+
+	page := object{
+		"_type":        "page",
+		"items":        items,
+		"limit":        limit,
+		"offset":       offset,
+		"total":        len(all),
+		"uri":          pageUri(offset),
+		"first_uri":    pageUri(0),
+		"last_uri":     pageUri(last),
+		"next_uri":     nil,
+		"previous_uri": nil,
+	}
+	if offset+limit < len(all) {
+		page["next_uri"] = pageUri(offset + limit)
+	}
+	if offset > 0 {
+		previous := offset - limit
+		if previous < 0 {
+			previous = 0
+		}
+		page["previous_uri"] = pageUri(previous)
+	}
+
+	return page
+}
+
+// Reports whether every filter matches a field of resource. Filters are
+// either a field name, or a field name followed by an operator in brackets,
+// i.e. amount[>=]. Meta values are filtered as meta[key].
+func matches(resource object, filters url.Values) bool {
+	for key, values := range filters {
+		field, op := resource[key], ""
+		if name, inner, ok := strings.Cut(strings.TrimSuffix(key, "]"), "["); ok {
+			if name == "meta" {
+				meta, _ := resource["meta"].(object)
+				field = meta[inner]
+			} else {
+				field, op = resource[name], inner
+			}
+		}
+
+		for _, value := range values {
+			if !matchesValue(field, op, value) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Reports whether field compares to value with op. List fields, like roles,
+// match when any of their items does.
+func matchesValue(field interface{}, op, value string) bool {
+	switch field := field.(type) {
+	case []interface{}:
+		if op == "!=" {
+			return !matchesValue(field, "", value)
+		}
+		for _, item := range field {
+			if matchesValue(item, op, value) {
+				return true
+			}
+		}
+		return false
+	case nil:
+		switch op {
+		case "":
+			return len(value) == 0
+		case "!=":
+			return len(value) != 0
+		}
+		return false
+	}
+
+	c := compareValues(fmt.Sprint(field), value)
+	switch op {
+	case "":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "in":
+		for _, v := range strings.Split(value, ",") {
+			if compareValues(fmt.Sprint(field), v) == 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Compares two values as numbers or times if both are, as strings otherwise.
+func compareValues(a, b string) int {
+	if x, err := strconv.ParseFloat(a, 64); err == nil {
+		if y, err := strconv.ParseFloat(b, 64); err == nil {
+			return cmp.Compare(x, y)
+		}
+	}
+
+	if x, err := time.Parse(time.RFC3339Nano, a); err == nil {
+		if y, err := time.Parse(time.RFC3339Nano, b); err == nil {
+			return x.Compare(y)
+		}
+	}
+
+	return strings.Compare(a, b)
+}
+
+// Sorts resources by the given "field,asc" or "field,desc" orders. Later
+// orders break ties of earlier ones, and resources that tie on every order
+// keep their place.
+func sortResources(resources []object, orders []string) {
+	if len(orders) == 0 {
+		return
+	}
+
+	sort.SliceStable(resources, func(i, j int) bool {
+		for _, order := range orders {
+			field, direction, _ := strings.Cut(order, ",")
+
+			c := compareValues(fmt.Sprint(resources[i][field]),
+				fmt.Sprint(resources[j][field]))
+			if direction == "desc" {
+				c = -c
+			}
+			if c != 0 {
+				return c < 0
+			}
+		}
+
+		return false
+	})
+}
+
+// Returns the uri one level up, i.e. the account of an account's debits uri.
+func parent(path string) string {
+	return path[:strings.LastIndex(path, "/")]
+}
+
+// Generates a new id with the given prefix.
+func (s *Server) newId(prefix string) string {
+	s.counter++
+
+	return fmt.Sprintf("%v%022d", prefix, s.counter)
+}
+
+func (s *Server) transactionNumber(prefix string) string {
+	s.counter++
+	n := s.counter
+
+	return fmt.Sprintf("%v%03d-%03d-%04d", prefix, n/10000000%1000,
+		n/10000%1000, n%10000)
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339Nano)
+}
+
+// Stores a resource and adds it to the given list resources.
+func (s *Server) store(resource object, lists ...string) object {
+	uri := resource["uri"].(string)
+	s.resources[uri] = resource
+
+	for _, list := range lists {
+		s.lists[list] = append(s.lists[list], uri)
+	}
+
+	return resource
+}
+
+// Removes a resource and its uri from every list resource.
+func (s *Server) remove(uri string) {
+	delete(s.resources, uri)
+
+	for list, uris := range s.lists {
+		for i, item := range uris {
+			if item == uri {
+				s.lists[list] = append(uris[:i:i], uris[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// Creates empty list resources for every given uri.
+func (s *Server) newLists(uris ...string) {
+	for _, uri := range uris {
+		s.lists[uri] = nil
+	}
+}
+
+// Looks up the resource named by a uri form parameter.
+func (s *Server) lookup(uri, resourceType, param string) (object, *apiError) {
+	if len(uri) == 0 {
+		return nil, badRequest("Missing required field [%v]", param)
+	}
+
+	resource, ok := s.resources[uri]
+	if !ok || resource["_type"] != resourceType {
+		return nil, badRequest("Invalid field [%v] - %v is not a valid %v uri",
+			param, uri, resourceType)
+	}
+
+	return resource, nil
+}
+
+// Records an event for a change to entity.
+func (s *Server) emit(eventType string, entity object) {
+	id := s.newId("EV")
+	s.store(object{
+		"_type":        "event",
+		"id":           id,
+		"uri":          eventsUri + "/" + id,
+		"type":         eventType,
+		"occurred_at":  now(),
+		"entity":       snapshot(entity),
+		"callback_uri": eventsUri + "/" + id + "/callbacks",
+		"callback_statuses": object{
+			"failed":    0,
+			"pending":   0,
+			"retrying":  0,
+			"succeeded": 0,
+		},
+	}, eventsUri)
+}
+
+// Returns a deep copy of a resource, for embedding in another one.
+func snapshot(resource object) object {
+	if resource == nil {
+		return nil
+	}
+
+	data, _ := json.Marshal(resource)
+	copied := object{}
+	json.Unmarshal(data, &copied)
+
+	return copied
+}
+
+// Parses a required, positive amount in cents.
+func parseAmount(form url.Values, required bool) (int, *apiError) {
+	value := form.Get("amount")
+	if len(value) == 0 && !required {
+		return 0, nil
+	}
+
+	amount, err := strconv.Atoi(value)
+	if err != nil || amount <= 0 {
+		return 0, badRequest("Invalid field [amount] - %q must be a positive "+
+			"integer amount in cents", value)
+	}
+
+	return amount, nil
+}
+
+// Collects meta[key] form values.
+func parseMeta(form url.Values, prefix string) object {
+	meta := object{}
+	for key, values := range form {
+		if strings.HasPrefix(key, prefix+"meta[") && strings.HasSuffix(key, "]") {
+			name := key[len(prefix)+len("meta[") : len(key)-1]
+			meta[name] = values[0]
+		}
+	}
+
+	return meta
+}
+
+// Merges meta[key] form values into the resource's meta.
+func updateMeta(resource object, form url.Values) {
+	meta, _ := resource["meta"].(object)
+	if meta == nil {
+		meta = object{}
+	}
+
+	for key, value := range parseMeta(form, "") {
+		meta[key] = value
+	}
+
+	resource["meta"] = meta
+}
+
+// Copies a form value into the resource, if present.
+func setString(resource object, form url.Values, key string) {
+	if _, ok := form[key]; ok {
+		resource[key] = form.Get(key)
+	}
+}
+
+// Returns the value of a form field or nil, for optional json fields.
+func optional(form url.Values, key string) interface{} {
+	if value := form.Get(key); len(value) != 0 {
+		return value
+	}
+
+	return nil
+}
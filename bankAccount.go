@@ -1,6 +1,7 @@
 package balanced
 
 import (
+	"context"
 	"net/url"
 	"strconv"
 	"time"
@@ -46,11 +47,11 @@ type ListOfBankAccounts struct {
 
 type Verification struct {
 	ApiDefaultResponse
-	Attempts          int    `json:"attempts,omitempty"`
-	Id                string `json:"id,omitempty"`
-	RemainingAttempts int    `json:"remaining_attempts,omitempty"`
-	State             string `json:"pending,omitempty"`
-	Uri               string `json:"uri,omitempty"`
+	Attempts          int               `json:"attempts,omitempty"`
+	Id                string            `json:"id,omitempty"`
+	RemainingAttempts int               `json:"remaining_attempts,omitempty"`
+	State             VerificationState `json:"state,omitempty"`
+	Uri               string            `json:"uri,omitempty"`
 }
 
 type ListOfVerifications struct {
@@ -69,9 +70,21 @@ type ListOfVerifications struct {
 // You'll eventually want to be able to credit bank accounts without having to
 // ask your users for their information over and over again. To do this, you'll
 // need to create a bank account object.
+// The details are checked with ValidateBankAccount before anything is sent.
 // NOTE To debit a bank account you must first verify it.
 // WARNING PCI Compliance required to use this functionality.
-func CreateNewBankAccount(name, accountNumber, routingNumber, accountType string) (bankAccount *BankAccount, err error) {
+func (c *Client) CreateNewBankAccount(name, accountNumber, routingNumber, accountType string) (bankAccount *BankAccount, err error) {
+	return c.CreateNewBankAccountContext(context.Background(), name,
+		accountNumber, routingNumber, accountType)
+}
+
+// CreateNewBankAccountContext is like CreateNewBankAccount but carries the given context.
+func (c *Client) CreateNewBankAccountContext(ctx context.Context, name, accountNumber, routingNumber, accountType string) (bankAccount *BankAccount, err error) {
+	err = ValidateBankAccount(name, accountNumber, routingNumber, accountType)
+	if err != nil {
+		return nil, err
+	}
+
 	payload := url.Values{
 		"name":           {name},
 		"account_number": {accountNumber},
@@ -80,82 +93,207 @@ func CreateNewBankAccount(name, accountNumber, routingNumber, accountType string
 	}
 
 	bankAccount = &BankAccount{}
-	err = post(bankAccountsUri, payload, bankAccount)
+	err = c.post(ctx, bankAccountsUri, payload, bankAccount)
+
+	// Fill in the bank name when balanced does not know it
+	if err == nil && len(bankAccount.BankName) == 0 {
+		bankAccount.BankName = c.LookupBankName(routingNumber)
+	}
 
 	return
 }
 
+// CreateNewBankAccount is a wrapper around DefaultClient.CreateNewBankAccount.
+func CreateNewBankAccount(name, accountNumber, routingNumber, accountType string) (bankAccount *BankAccount, err error) {
+	return DefaultClient.CreateNewBankAccount(name, accountNumber,
+		routingNumber, accountType)
+}
+
+// CreateNewBankAccountContext is a wrapper around DefaultClient.CreateNewBankAccountContext.
+func CreateNewBankAccountContext(ctx context.Context, name, accountNumber, routingNumber, accountType string) (bankAccount *BankAccount, err error) {
+	return DefaultClient.CreateNewBankAccountContext(ctx, name, accountNumber,
+		routingNumber, accountType)
+}
+
 // Retrieves the details of a bank account that has previously been created.
 // Supply the uri that was returned from your previous request, and the
 // corresponding bank account information will be returned. The same information
 // is returned when creating the bank account.
 // uri: In the form of /v1/bank_accounts/:bank_account_id
-func RetrieveBankAccount(uri string) (bankAccount *BankAccount, err error) {
+func (c *Client) RetrieveBankAccount(uri string) (bankAccount *BankAccount, err error) {
+	return c.RetrieveBankAccountContext(context.Background(), uri)
+}
+
+// RetrieveBankAccountContext is like RetrieveBankAccount but carries the given context.
+func (c *Client) RetrieveBankAccountContext(ctx context.Context, uri string) (bankAccount *BankAccount, err error) {
 	bankAccount = &BankAccount{}
-	err = get(uri, nil, bankAccount)
+	err = c.get(ctx, uri, nil, bankAccount)
 
 	return
 }
 
+// RetrieveBankAccount is a wrapper around DefaultClient.RetrieveBankAccount.
+func RetrieveBankAccount(uri string) (bankAccount *BankAccount, err error) {
+	return DefaultClient.RetrieveBankAccount(uri)
+}
+
+// RetrieveBankAccountContext is a wrapper around DefaultClient.RetrieveBankAccountContext.
+func RetrieveBankAccountContext(ctx context.Context, uri string) (bankAccount *BankAccount, err error) {
+	return DefaultClient.RetrieveBankAccountContext(ctx, uri)
+}
+
 // Returns a list of bank accounts that you've created but haven't deleted.
-func ListAllBankAccounts(limit, offset int) (listOfBankAccounts *ListOfBankAccounts, err error) {
+func (c *Client) ListAllBankAccounts(limit, offset int) (listOfBankAccounts *ListOfBankAccounts, err error) {
+	return c.ListAllBankAccountsContext(context.Background(), limit, offset)
+}
+
+// ListAllBankAccountsContext is like ListAllBankAccounts but carries the given context.
+func (c *Client) ListAllBankAccountsContext(ctx context.Context, limit, offset int) (listOfBankAccounts *ListOfBankAccounts, err error) {
 	payload := defaultPayload(limit, offset)
 
 	listOfBankAccounts = &ListOfBankAccounts{}
-	err = get(bankAccountsUri, payload, listOfBankAccounts)
+	err = c.get(ctx, bankAccountsUri, payload, listOfBankAccounts)
 
 	return
 }
 
+// ListAllBankAccounts is a wrapper around DefaultClient.ListAllBankAccounts.
+func ListAllBankAccounts(limit, offset int) (listOfBankAccounts *ListOfBankAccounts, err error) {
+	return DefaultClient.ListAllBankAccounts(limit, offset)
+}
+
+// ListAllBankAccountsContext is a wrapper around DefaultClient.ListAllBankAccountsContext.
+func ListAllBankAccountsContext(ctx context.Context, limit, offset int) (listOfBankAccounts *ListOfBankAccounts, err error) {
+	return DefaultClient.ListAllBankAccountsContext(ctx, limit, offset)
+}
+
 // Permanently delete a bank account. It cannot be undone. All associated
 // credits with a deleted bank account will not be affected.
 // uri: In the form of /v1/bank_accounts/:bank_account_id
-func DeleteBankAccount(uri string) (err error) {
-	err = delete(uri, nil, nil)
+func (c *Client) DeleteBankAccount(uri string) (err error) {
+	return c.DeleteBankAccountContext(context.Background(), uri)
+}
+
+// DeleteBankAccountContext is like DeleteBankAccount but carries the given context.
+func (c *Client) DeleteBankAccountContext(ctx context.Context, uri string) (err error) {
+	err = c.delete(ctx, uri, nil, nil)
 
 	return
 }
 
+// DeleteBankAccount is a wrapper around DefaultClient.DeleteBankAccount.
+func DeleteBankAccount(uri string) (err error) {
+	return DefaultClient.DeleteBankAccount(uri)
+}
+
+// DeleteBankAccountContext is a wrapper around DefaultClient.DeleteBankAccountContext.
+func DeleteBankAccountContext(ctx context.Context, uri string) (err error) {
+	return DefaultClient.DeleteBankAccountContext(ctx, uri)
+}
+
 // Creates a new bank account verification.
 // uri: In the form of /v1/bank_accounts/:bank_account_id
-func VerifyBankAccount(uri string) (verification *Verification, err error) {
+func (c *Client) VerifyBankAccount(uri string) (verification *Verification, err error) {
+	return c.VerifyBankAccountContext(context.Background(), uri)
+}
+
+// VerifyBankAccountContext is like VerifyBankAccount but carries the given context.
+func (c *Client) VerifyBankAccountContext(ctx context.Context, uri string) (verification *Verification, err error) {
 	uri += "/verifications"
 
 	verification = &Verification{}
-	err = post(uri, nil, verification)
+	err = c.post(ctx, uri, nil, verification)
 
 	return
 }
 
+// VerifyBankAccount is a wrapper around DefaultClient.VerifyBankAccount.
+func VerifyBankAccount(uri string) (verification *Verification, err error) {
+	return DefaultClient.VerifyBankAccount(uri)
+}
+
+// VerifyBankAccountContext is a wrapper around DefaultClient.VerifyBankAccountContext.
+func VerifyBankAccountContext(ctx context.Context, uri string) (verification *Verification, err error) {
+	return DefaultClient.VerifyBankAccountContext(ctx, uri)
+}
+
 // Retrieve a Verification for a Bank Account
 // uri: /v1/bank_accounts/:bank_account_id/verifications/:verification_id
-func RetrieveBankAccountVerification(uri string) (verification *Verification, err error) {
+func (c *Client) RetrieveBankAccountVerification(uri string) (verification *Verification, err error) {
+	return c.RetrieveBankAccountVerificationContext(context.Background(), uri)
+}
+
+// RetrieveBankAccountVerificationContext is like RetrieveBankAccountVerification but carries the given context.
+func (c *Client) RetrieveBankAccountVerificationContext(ctx context.Context, uri string) (verification *Verification, err error) {
 	verification = &Verification{}
-	err = get(uri, nil, verification)
+	err = c.get(ctx, uri, nil, verification)
 
 	return
 }
 
+// RetrieveBankAccountVerification is a wrapper around DefaultClient.RetrieveBankAccountVerification.
+func RetrieveBankAccountVerification(uri string) (verification *Verification, err error) {
+	return DefaultClient.RetrieveBankAccountVerification(uri)
+}
+
+// RetrieveBankAccountVerificationContext is a wrapper around DefaultClient.RetrieveBankAccountVerificationContext.
+func RetrieveBankAccountVerificationContext(ctx context.Context, uri string) (verification *Verification, err error) {
+	return DefaultClient.RetrieveBankAccountVerificationContext(ctx, uri)
+}
+
 // List All Verifications for a Bank Account
 // uri: In the form of /v1/bank_accounts/:bank_account_id/verifications
-func ListAllBankAccountVerifications(uri string) (listOfVerifications *ListOfVerifications, err error) {
+func (c *Client) ListAllBankAccountVerifications(uri string) (listOfVerifications *ListOfVerifications, err error) {
+	return c.ListAllBankAccountVerificationsContext(context.Background(), uri)
+}
+
+// ListAllBankAccountVerificationsContext is like ListAllBankAccountVerifications but carries the given context.
+func (c *Client) ListAllBankAccountVerificationsContext(ctx context.Context, uri string) (listOfVerifications *ListOfVerifications, err error) {
 	listOfVerifications = &ListOfVerifications{}
-	err = get(uri, nil, listOfVerifications)
+	err = c.get(ctx, uri, nil, listOfVerifications)
 
 	return
 }
 
+// ListAllBankAccountVerifications is a wrapper around DefaultClient.ListAllBankAccountVerifications.
+func ListAllBankAccountVerifications(uri string) (listOfVerifications *ListOfVerifications, err error) {
+	return DefaultClient.ListAllBankAccountVerifications(uri)
+}
+
+// ListAllBankAccountVerificationsContext is a wrapper around DefaultClient.ListAllBankAccountVerificationsContext.
+func ListAllBankAccountVerificationsContext(ctx context.Context, uri string) (listOfVerifications *ListOfVerifications, err error) {
+	return DefaultClient.ListAllBankAccountVerificationsContext(ctx, uri)
+}
+
 // Confirms the trial deposit amounts. For the test environment the trial
 // deposit amounts are always 1 and 1.
 // uri: /v1/bank_accounts/:bank_account_id/verifications/:verification_id
-func ConfirmBankAccountVerification(uri string, amountOne, amountTwo int64) (verification *Verification, err error) {
+func (c *Client) ConfirmBankAccountVerification(uri string, amountOne, amountTwo int64) (verification *Verification, err error) {
+	return c.ConfirmBankAccountVerificationContext(context.Background(), uri,
+		amountOne, amountTwo)
+}
+
+// ConfirmBankAccountVerificationContext is like ConfirmBankAccountVerification but carries the given context.
+func (c *Client) ConfirmBankAccountVerificationContext(ctx context.Context, uri string, amountOne, amountTwo int64) (verification *Verification, err error) {
 	payload := url.Values{
 		"amount_1": {strconv.FormatInt(amountOne, 10)},
 		"amount_2": {strconv.FormatInt(amountTwo, 10)},
 	}
 
 	verification = &Verification{}
-	err = put(uri, payload, verification)
+	err = c.put(ctx, uri, payload, verification)
 
 	return
 }
+
+// ConfirmBankAccountVerification is a wrapper around DefaultClient.ConfirmBankAccountVerification.
+func ConfirmBankAccountVerification(uri string, amountOne, amountTwo int64) (verification *Verification, err error) {
+	return DefaultClient.ConfirmBankAccountVerification(uri, amountOne,
+		amountTwo)
+}
+
+// ConfirmBankAccountVerificationContext is a wrapper around DefaultClient.ConfirmBankAccountVerificationContext.
+func ConfirmBankAccountVerificationContext(ctx context.Context, uri string, amountOne, amountTwo int64) (verification *Verification, err error) {
+	return DefaultClient.ConfirmBankAccountVerificationContext(ctx, uri,
+		amountOne, amountTwo)
+}
@@ -0,0 +1,62 @@
+package balanced
+
+const (
+	// Length bounds of US bank account numbers.
+	minAccountNumberLength = 4
+	maxAccountNumberLength = 17
+)
+
+// Checks bank account details the way balanced would, without contacting it:
+// the routing number must pass the ABA checksum, the account number must be
+// 4 to 17 digits and the type must be BankAccountTypeChecking or
+// BankAccountTypeSavings. Returns a *ValidationError listing every invalid
+// field.
+func ValidateBankAccount(name, accountNumber, routingNumber, accountType string) error {
+	v := &ValidationError{}
+
+	if len(name) == 0 {
+		v.add("name", "is required")
+	}
+
+	switch {
+	case len(accountNumber) == 0:
+		v.add("account_number", "is required")
+	case !isDigits(accountNumber):
+		v.add("account_number", "must only contain digits")
+	case len(accountNumber) < minAccountNumberLength || len(accountNumber) > maxAccountNumberLength:
+		v.add("account_number", "must be between %v and %v digits long, got %v",
+			minAccountNumberLength, maxAccountNumberLength, len(accountNumber))
+	}
+
+	switch {
+	case len(routingNumber) == 0:
+		v.add("routing_number", "is required")
+	case !isDigits(routingNumber) || len(routingNumber) != 9:
+		v.add("routing_number", "must be 9 digits, got %q", routingNumber)
+	case !routingNumberValid(routingNumber):
+		v.add("routing_number", "%v is not a valid ABA routing number",
+			routingNumber)
+	}
+
+	if accountType != BankAccountTypeChecking && accountType != BankAccountTypeSavings {
+		v.add("type", "must be %v or %v, got %q", BankAccountTypeChecking,
+			BankAccountTypeSavings, accountType)
+	}
+
+	return v.err()
+}
+
+// Reports whether a 9 digit routing number passes the ABA checksum.
+func routingNumberValid(number string) bool {
+	if len(number) != 9 || !isDigits(number) {
+		return false
+	}
+
+	weights := [9]int{3, 7, 1, 3, 7, 1, 3, 7, 1}
+	sum := 0
+	for i := range number {
+		sum += int(number[i]-'0') * weights[i]
+	}
+
+	return sum%10 == 0
+}
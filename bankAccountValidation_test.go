@@ -0,0 +1,73 @@
+package balanced
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestValidateBankAccount(t *testing.T) {
+	tests := []struct {
+		name, accountNumber, routingNumber, accountType string
+		// Invalid fields, in order
+		fields []string
+	}{
+		{"Johann Bernoulli", "9900000001", "121000358", BankAccountTypeChecking, nil},
+		{"Johann Bernoulli", "0001", "021000021", BankAccountTypeSavings, nil},
+		{"", "9900000001", "121000358", BankAccountTypeChecking, []string{"name"}},
+		{"Johann Bernoulli", "990-000-0001", "121000358", BankAccountTypeChecking, []string{"account_number"}},
+		{"Johann Bernoulli", "123", "121000358", BankAccountTypeChecking, []string{"account_number"}},
+		{"Johann Bernoulli", "123456789012345678", "121000358", BankAccountTypeChecking, []string{"account_number"}},
+		{"Johann Bernoulli", "9900000001", "121000359", BankAccountTypeChecking, []string{"routing_number"}},
+		{"Johann Bernoulli", "9900000001", "12100035", BankAccountTypeChecking, []string{"routing_number"}},
+		{"Johann Bernoulli", "9900000001", "121000358", "business", []string{"type"}},
+		{"", "", "", "", []string{"name", "account_number", "routing_number", "type"}},
+	}
+
+	for _, test := range tests {
+		err := ValidateBankAccount(test.name, test.accountNumber,
+			test.routingNumber, test.accountType)
+
+		if len(test.fields) == 0 {
+			if err != nil {
+				t.Errorf("Expected %v to be valid, got %v", test, err)
+			}
+			continue
+		}
+
+		var validationError *ValidationError
+		if !errors.As(err, &validationError) || !errors.Is(err, ErrValidation) ||
+			len(validationError.Errors) != len(test.fields) {
+			t.Errorf("Expected invalid fields %v, got %v", test.fields, err)
+			continue
+		}
+
+		for i, fieldError := range validationError.Errors {
+			if fieldError.Field != test.fields[i] {
+				t.Errorf("Expected invalid fields %v, got %v", test.fields, err)
+				break
+			}
+		}
+	}
+}
+
+func TestCreateNewBankAccountValidation(t *testing.T) {
+	// Invalid bank accounts never reach balanced
+	client := newStaticClient(http.StatusInternalServerError, "")
+
+	_, err := client.CreateNewBankAccount("Johann Bernoulli", "9900000001",
+		"121000359", BankAccountTypeChecking)
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("Expected a validation error, got %v", err)
+	}
+
+	_, err = client.CreditNewBankAccount(50, "Testing credit", &BankAccount{
+		Name:          "Johann Bernoulli",
+		AccountNumber: "9900000001",
+		RoutingNumber: "121000358",
+		Type:          "business",
+	})
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("Expected a validation error, got %v", err)
+	}
+}
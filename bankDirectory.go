@@ -0,0 +1,212 @@
+package balanced
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// A small directory of large US banks, used when a client has no
+// BankDirectory of its own.
+//
+//go:embed banks.csv
+var bundledBanks []byte
+
+var (
+	defaultBankDirectory     *BankDirectory
+	defaultBankDirectoryOnce sync.Once
+)
+
+// A financial institution, as listed in a bank directory.
+type Bank struct {
+	RoutingNumber string
+	Name          string
+	City          string
+	State         string
+}
+
+// Resolves routing numbers to banks without contacting balanced. Load the
+// full directory with LoadBankDirectory, the bundled one only knows about a
+// few large banks.
+type BankDirectory struct {
+	banks map[string]*Bank
+}
+
+// Returns the directory bundled with the package.
+func DefaultBankDirectory() *BankDirectory {
+	defaultBankDirectoryOnce.Do(func() {
+		directory, err := ParseBankDirectory(bytes.NewReader(bundledBanks))
+		if err != nil {
+			panic(err)
+		}
+
+		defaultBankDirectory = directory
+	})
+
+	return defaultBankDirectory
+}
+
+// Loads a bank directory file, either the Federal Reserve's fixed width
+// FedACH directory (FedACHdir.txt) or a csv file with a header row naming
+// routing_number, name and optionally city and state columns.
+func LoadBankDirectory(path string) (*BankDirectory, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Balanced API: Unable to read bank directory: %w", err)
+	}
+	defer file.Close()
+
+	directory, err := ParseBankDirectory(file)
+	if err != nil {
+		return nil, fmt.Errorf("Balanced API: Unable to parse bank directory %v: %w", path, err)
+	}
+
+	return directory, nil
+}
+
+// Parses a bank directory in either of the formats LoadBankDirectory accepts.
+func ParseBankDirectory(r io.Reader) (*BankDirectory, error) {
+	reader := bufio.NewReader(r)
+
+	first, err := reader.Peek(10)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	// FedACH records start with the routing number followed by an office code
+	if len(first) == 10 && isDigits(string(first[:9])) && first[9] != ',' {
+		return parseFedACHDirectory(reader)
+	}
+
+	return parseCSVBankDirectory(reader)
+}
+
+// Field positions in a FedACH directory record.
+const (
+	fedACHRecordLength = 129
+	fedACHNameStart    = 35
+	fedACHNameEnd      = 71
+	fedACHCityStart    = 107
+	fedACHCityEnd      = 127
+	fedACHStateEnd     = 129
+)
+
+func parseFedACHDirectory(r io.Reader) (*BankDirectory, error) {
+	directory := &BankDirectory{banks: map[string]*Bank{}}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		record := scanner.Text()
+		if len(strings.TrimSpace(record)) == 0 {
+			continue
+		}
+
+		if len(record) < fedACHRecordLength {
+			return nil, fmt.Errorf("line %v: record is %v characters, expected at least %v",
+				line, len(record), fedACHRecordLength)
+		}
+
+		directory.add(&Bank{
+			RoutingNumber: record[:9],
+			Name:          strings.TrimSpace(record[fedACHNameStart:fedACHNameEnd]),
+			City:          strings.TrimSpace(record[fedACHCityStart:fedACHCityEnd]),
+			State:         record[fedACHCityEnd:fedACHStateEnd],
+		})
+	}
+
+	return directory, scanner.Err()
+}
+
+func parseCSVBankDirectory(r io.Reader) (*BankDirectory, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, name := range []string{"routing_number", "name"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing %v column", name)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+
+		return strings.TrimSpace(record[i])
+	}
+
+	directory := &BankDirectory{banks: map[string]*Bank{}}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		directory.add(&Bank{
+			RoutingNumber: field(record, "routing_number"),
+			Name:          field(record, "name"),
+			City:          field(record, "city"),
+			State:         field(record, "state"),
+		})
+	}
+
+	return directory, nil
+}
+
+func (d *BankDirectory) add(bank *Bank) {
+	if len(bank.RoutingNumber) != 0 {
+		d.banks[bank.RoutingNumber] = bank
+	}
+}
+
+// Returns the bank a routing number belongs to, or nil if it is not listed.
+func (d *BankDirectory) Lookup(routingNumber string) *Bank {
+	return d.banks[routingNumber]
+}
+
+// Number of banks in the directory.
+func (d *BankDirectory) Len() int {
+	return len(d.banks)
+}
+
+// Returns the name of the bank a routing number belongs to, looked up in the
+// client's BankDirectory, or the bundled directory if it has none. Returns an
+// empty string if the routing number is not listed.
+func (c *Client) LookupBankName(routingNumber string) string {
+	directory := c.BankDirectory
+	if directory == nil {
+		directory = DefaultBankDirectory()
+	}
+
+	if bank := directory.Lookup(routingNumber); bank != nil {
+		return bank.Name
+	}
+
+	return ""
+}
+
+// LookupBankName is a wrapper around DefaultClient.LookupBankName.
+func LookupBankName(routingNumber string) string {
+	return DefaultClient.LookupBankName(routingNumber)
+}
@@ -0,0 +1,65 @@
+package balanced
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDefaultBankDirectory(t *testing.T) {
+	directory := DefaultBankDirectory()
+	if directory.Len() == 0 {
+		t.Fatal("Bundled bank directory is empty")
+	}
+
+	bank := directory.Lookup("021000021")
+	if bank == nil || bank.Name != "JPMORGAN CHASE BANK N.A." {
+		t.Fatalf("Invalid bank found: %v", bank)
+	}
+
+	if directory.Lookup("121000359") != nil {
+		t.Fatal("Found a bank for an unknown routing number")
+	}
+
+	if LookupBankName("121000358") != "BANK OF AMERICA N.A." {
+		t.Fatalf("Invalid bank name: %v", LookupBankName("121000358"))
+	}
+}
+
+func TestLoadBankDirectory(t *testing.T) {
+	directory, err := LoadBankDirectory("testdata/FedACHdir.txt")
+	if err != nil {
+		t.Fatalf("Failed to load FedACH directory: %v", err)
+	}
+
+	bank := directory.Lookup("011000015")
+	if directory.Len() != 3 || bank == nil || bank.Name != "FEDERAL RESERVE BANK" ||
+		bank.City != "ATLANTA" || bank.State != "GA" {
+		t.Fatalf("Invalid bank found: %v", bank)
+	}
+
+	// Clients look bank names up in their own directory
+	client := NewClient("http://balanced.invalid", "ak-test-key", "TEST-MP123")
+	client.BankDirectory = directory
+
+	if client.LookupBankName("011000015") != "FEDERAL RESERVE BANK" ||
+		len(client.LookupBankName("091000019")) != 0 {
+		t.Fatal("Client did not use its bank directory")
+	}
+
+	directory, err = ParseBankDirectory(strings.NewReader(
+		"Name, Routing_Number, State\n" +
+			"\"FIRST BANK, INC.\", 011000015, CO\n"))
+	if err != nil {
+		t.Fatalf("Failed to parse csv directory: %v", err)
+	}
+
+	bank = directory.Lookup("011000015")
+	if bank == nil || bank.Name != "FIRST BANK, INC." || bank.State != "CO" {
+		t.Fatalf("Invalid bank found: %v", bank)
+	}
+
+	_, err = ParseBankDirectory(strings.NewReader("routing,bank\n011000015,X\n"))
+	if err == nil {
+		t.Fatal("Parsed a directory without a routing_number column")
+	}
+}
@@ -0,0 +1,212 @@
+package balanced
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/url"
+	"time"
+)
+
+const (
+	callbacksUri = marketplaceUri + "/%v/callbacks"
+)
+
+type Callback struct {
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	Id        string    `json:"id,omitempty"`
+	Method    string    `json:"method,omitempty"`
+	Uri       string    `json:"uri,omitempty"`
+	Url       string    `json:"url,omitempty"`
+}
+
+type ListOfCallbacks = Page[Callback]
+
+// Registers a url that balanced posts every event of the marketplace to. See
+// CallbackReceiver for handling them.
+func (c *Client) CreateCallback(callbackUrl string) (callback *Callback, err error) {
+	return c.CreateCallbackContext(context.Background(), callbackUrl)
+}
+
+// CreateCallbackContext is like CreateCallback but carries the given context.
+func (c *Client) CreateCallbackContext(ctx context.Context, callbackUrl string) (callback *Callback, err error) {
+	payload := url.Values{
+		"url": {callbackUrl},
+	}
+
+	uri := fmt.Sprintf(callbacksUri, c.MarketplaceId)
+
+	callback = &Callback{}
+	err = c.post(ctx, uri, payload, callback)
+
+	return
+}
+
+// CreateCallback is a wrapper around DefaultClient.CreateCallback.
+func CreateCallback(callbackUrl string) (callback *Callback, err error) {
+	return DefaultClient.CreateCallback(callbackUrl)
+}
+
+// CreateCallbackContext is a wrapper around DefaultClient.CreateCallbackContext.
+func CreateCallbackContext(ctx context.Context, callbackUrl string) (callback *Callback, err error) {
+	return DefaultClient.CreateCallbackContext(ctx, callbackUrl)
+}
+
+// Retrieves the details of a callback that was previously registered.
+func (c *Client) RetrieveCallback(uri string) (callback *Callback, err error) {
+	return c.RetrieveCallbackContext(context.Background(), uri)
+}
+
+// RetrieveCallbackContext is like RetrieveCallback but carries the given context.
+func (c *Client) RetrieveCallbackContext(ctx context.Context, uri string) (callback *Callback, err error) {
+	callback = &Callback{}
+	err = c.get(ctx, uri, nil, callback)
+
+	return
+}
+
+// RetrieveCallback is a wrapper around DefaultClient.RetrieveCallback.
+func RetrieveCallback(uri string) (callback *Callback, err error) {
+	return DefaultClient.RetrieveCallback(uri)
+}
+
+// RetrieveCallbackContext is a wrapper around DefaultClient.RetrieveCallbackContext.
+func RetrieveCallbackContext(ctx context.Context, uri string) (callback *Callback, err error) {
+	return DefaultClient.RetrieveCallbackContext(ctx, uri)
+}
+
+// Returns a list of the callbacks registered for the marketplace.
+func (c *Client) ListCallbacks(limit, offset int) (listOfCallbacks *ListOfCallbacks, err error) {
+	return c.ListCallbacksContext(context.Background(), limit, offset)
+}
+
+// ListCallbacksContext is like ListCallbacks but carries the given context.
+func (c *Client) ListCallbacksContext(ctx context.Context, limit, offset int) (listOfCallbacks *ListOfCallbacks, err error) {
+	payload := defaultPayload(limit, offset)
+
+	uri := fmt.Sprintf(callbacksUri, c.MarketplaceId)
+
+	listOfCallbacks = &ListOfCallbacks{}
+	err = c.get(ctx, uri, payload, listOfCallbacks)
+
+	return
+}
+
+// ListCallbacks is a wrapper around DefaultClient.ListCallbacks.
+func ListCallbacks(limit, offset int) (listOfCallbacks *ListOfCallbacks, err error) {
+	return DefaultClient.ListCallbacks(limit, offset)
+}
+
+// ListCallbacksContext is a wrapper around DefaultClient.ListCallbacksContext.
+func ListCallbacksContext(ctx context.Context, limit, offset int) (listOfCallbacks *ListOfCallbacks, err error) {
+	return DefaultClient.ListCallbacksContext(ctx, limit, offset)
+}
+
+// Unregisters a callback. Balanced stops posting events to its url.
+func (c *Client) DeleteCallback(uri string) (err error) {
+	return c.DeleteCallbackContext(context.Background(), uri)
+}
+
+// DeleteCallbackContext is like DeleteCallback but carries the given context.
+func (c *Client) DeleteCallbackContext(ctx context.Context, uri string) (err error) {
+	err = c.delete(ctx, uri, nil, nil)
+
+	return
+}
+
+// DeleteCallback is a wrapper around DefaultClient.DeleteCallback.
+func DeleteCallback(uri string) (err error) {
+	return DefaultClient.DeleteCallback(uri)
+}
+
+// DeleteCallbackContext is a wrapper around DefaultClient.DeleteCallbackContext.
+func DeleteCallbackContext(ctx context.Context, uri string) (err error) {
+	return DefaultClient.DeleteCallbackContext(ctx, uri)
+}
+
+// Iterates over every callback registered for the marketplace.
+func (c *Client) IterateCallbacks(ctx context.Context, options *IterOptions) iter.Seq2[Callback, error] {
+	uri := fmt.Sprintf(callbacksUri, c.MarketplaceId)
+
+	return Iterate[Callback](ctx, c, uri, options)
+}
+
+// IterateCallbacks is a wrapper around DefaultClient.IterateCallbacks.
+func IterateCallbacks(ctx context.Context, options *IterOptions) iter.Seq2[Callback, error] {
+	return DefaultClient.IterateCallbacks(ctx, options)
+}
+
+// The changes SyncCallbacks made.
+type CallbackChanges struct {
+	Created []Callback
+	Deleted []Callback
+}
+
+// Makes the marketplace's callbacks match the given urls: urls that are not
+// registered yet are created, and callbacks for any other url, or registered
+// twice, are deleted. Missing callbacks are created before any is deleted, so
+// events keep being delivered while moving to a new url. On error, changes
+// holds what was done so far.
+func (c *Client) SyncCallbacks(urls []string) (changes *CallbackChanges, err error) {
+	return c.SyncCallbacksContext(context.Background(), urls)
+}
+
+// SyncCallbacksContext is like SyncCallbacks but carries the given context.
+func (c *Client) SyncCallbacksContext(ctx context.Context, urls []string) (changes *CallbackChanges, err error) {
+	changes = &CallbackChanges{}
+
+	wanted := map[string]bool{}
+	for _, callbackUrl := range urls {
+		wanted[callbackUrl] = true
+	}
+
+	// Keep the first callback of each wanted url
+	registered := map[string]bool{}
+	stale := []Callback{}
+	for callback, err := range c.IterateCallbacks(ctx, nil) {
+		if err != nil {
+			return changes, err
+		}
+
+		if wanted[callback.Url] && !registered[callback.Url] {
+			registered[callback.Url] = true
+			continue
+		}
+
+		stale = append(stale, callback)
+	}
+
+	for _, callbackUrl := range urls {
+		if registered[callbackUrl] {
+			continue
+		}
+
+		callback, err := c.CreateCallbackContext(ctx, callbackUrl)
+		if err != nil {
+			return changes, err
+		}
+
+		registered[callbackUrl] = true
+		changes.Created = append(changes.Created, *callback)
+	}
+
+	for _, callback := range stale {
+		if err := c.DeleteCallbackContext(ctx, callback.Uri); err != nil {
+			return changes, err
+		}
+
+		changes.Deleted = append(changes.Deleted, callback)
+	}
+
+	return changes, nil
+}
+
+// SyncCallbacks is a wrapper around DefaultClient.SyncCallbacks.
+func SyncCallbacks(urls []string) (changes *CallbackChanges, err error) {
+	return DefaultClient.SyncCallbacks(urls)
+}
+
+// SyncCallbacksContext is a wrapper around DefaultClient.SyncCallbacksContext.
+func SyncCallbacksContext(ctx context.Context, urls []string) (changes *CallbackChanges, err error) {
+	return DefaultClient.SyncCallbacksContext(ctx, urls)
+}
@@ -0,0 +1,246 @@
+package balanced
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+const testCallback = `{
+	"id": "EV1",
+	"type": "debit.succeeded",
+	"uri": "/v1/events/EV1",
+	"occurred_at": "2013-06-04T20:17:28.000Z",
+	"entity": {"id": "WD1", "uri": "/v1/marketplaces/TEST-MP123/debits/WD1"}
+}`
+
+func postCallback(receiver *CallbackReceiver, target, body string, setup func(*http.Request)) int {
+	req := httptest.NewRequest("POST", target, strings.NewReader(body))
+	req.RemoteAddr = "50.18.199.26:4321"
+	if setup != nil {
+		setup(req)
+	}
+
+	recorder := httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, req)
+
+	return recorder.Code
+}
+
+func TestCallbackReceiver(t *testing.T) {
+	receiver := NewCallbackReceiver()
+
+	var debits, all []string
+	fail := true
+	receiver.HandleFunc("debit.succeeded", func(ctx context.Context, event *Event) error {
+		debits = append(debits, event.Id)
+		if fail {
+			fail = false
+			return errors.New("database unavailable")
+		}
+		return nil
+	})
+	receiver.HandleFunc(AnyEventType, func(ctx context.Context, event *Event) error {
+		all = append(all, event.Type)
+		return nil
+	})
+
+	// Failures are answered with a 500 so that balanced retries
+	if code := postCallback(receiver, "/balanced", testCallback, nil); code != http.StatusInternalServerError {
+		t.Fatalf("Expected failed event to be retried, got %v", code)
+	}
+
+	if code := postCallback(receiver, "/balanced", testCallback, nil); code != http.StatusOK {
+		t.Fatalf("Expected event to be handled, got %v", code)
+	}
+
+	// Duplicate deliveries are acknowledged but not dispatched
+	if code := postCallback(receiver, "/balanced", testCallback, nil); code != http.StatusOK {
+		t.Fatalf("Expected duplicate event to be acknowledged, got %v", code)
+	}
+
+	// Events without a handler of their own only reach AnyEventType handlers
+	code := postCallback(receiver, "/balanced", `{"id": "EV2", "type": "hold.created"}`, nil)
+	if code != http.StatusOK {
+		t.Fatalf("Expected event to be handled, got %v", code)
+	}
+
+	if strings.Join(debits, ",") != "EV1,EV1" ||
+		strings.Join(all, ",") != "debit.succeeded,hold.created" {
+		t.Fatalf("Invalid dispatch: debits=%v all=%v", debits, all)
+	}
+
+	if code := postCallback(receiver, "/balanced", `{"type": "hold.created"}`, nil); code != http.StatusBadRequest {
+		t.Fatalf("Expected event without an id to be rejected, got %v", code)
+	}
+
+	if code := postCallback(receiver, "/balanced", "not json", nil); code != http.StatusBadRequest {
+		t.Fatalf("Expected invalid json to be rejected, got %v", code)
+	}
+
+	req := httptest.NewRequest("GET", "/balanced", nil)
+	recorder := httptest.NewRecorder()
+	receiver.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("Expected GET to be rejected, got %v", recorder.Code)
+	}
+}
+
+func TestCallbackReceiverAuthentication(t *testing.T) {
+	receiver := NewCallbackReceiver()
+	receiver.Secret = "s3cret"
+	receiver.Username = "balanced"
+	receiver.Password = "hunter2"
+	receiver.AllowedIPs = []netip.Prefix{netip.MustParsePrefix("50.18.199.0/24")}
+
+	handled := 0
+	receiver.HandleFunc(AnyEventType, func(ctx context.Context, event *Event) error {
+		handled++
+		return nil
+	})
+
+	basicAuth := func(req *http.Request) {
+		req.SetBasicAuth("balanced", "hunter2")
+	}
+
+	tests := []struct {
+		target string
+		setup  func(*http.Request)
+		code   int
+	}{
+		{"/balanced?secret=s3cret", nil, http.StatusUnauthorized},
+		{"/balanced?secret=wrong", basicAuth, http.StatusUnauthorized},
+		{"/balanced", basicAuth, http.StatusUnauthorized},
+		{"/balanced?secret=s3cret", func(req *http.Request) {
+			req.SetBasicAuth("balanced", "wrong")
+		}, http.StatusUnauthorized},
+		{"/balanced?secret=s3cret", func(req *http.Request) {
+			basicAuth(req)
+			req.RemoteAddr = "10.0.0.1:4321"
+		}, http.StatusForbidden},
+		{"/balanced?secret=s3cret", basicAuth, http.StatusOK},
+	}
+
+	for _, test := range tests {
+		if code := postCallback(receiver, test.target, testCallback, test.setup); code != test.code {
+			t.Errorf("Expected %v for %v, got %v", test.code, test.target, code)
+		}
+	}
+
+	if handled != 1 {
+		t.Fatalf("Expected a single authenticated event, got %v", handled)
+	}
+
+	// Behind a proxy the client ip comes from X-Forwarded-For
+	receiver.TrustForwardedFor = true
+	code := postCallback(receiver, "/balanced?secret=s3cret", `{"id": "EV2"}`, func(req *http.Request) {
+		basicAuth(req)
+		req.RemoteAddr = "10.0.0.1:4321"
+		req.Header.Set("X-Forwarded-For", "10.0.0.2, 50.18.199.26")
+	})
+	if code != http.StatusOK {
+		t.Fatalf("Expected forwarded callback to be allowed, got %v", code)
+	}
+}
+
+func TestCallbackReceiverForgetsOldEvents(t *testing.T) {
+	receiver := NewCallbackReceiver()
+	receiver.MaxSeenEvents = 2
+
+	handled := 0
+	receiver.HandleFunc(AnyEventType, func(ctx context.Context, event *Event) error {
+		handled++
+		return nil
+	})
+
+	for _, id := range []string{"EV1", "EV2", "EV3", "EV1"} {
+		postCallback(receiver, "/balanced", `{"id": "`+id+`"}`, nil)
+	}
+
+	if handled != 4 || len(receiver.seenOrder) != 2 {
+		t.Fatalf("Expected EV1 to be forgotten, handled %v", handled)
+	}
+}
+
+func TestCallbackReceiverConcurrentDuplicates(t *testing.T) {
+	receiver := NewCallbackReceiver()
+
+	started := make(chan struct{})
+	finish := make(chan error)
+	handled := 0
+	receiver.HandleFunc("debit.succeeded", func(ctx context.Context, event *Event) error {
+		handled++
+		started <- struct{}{}
+		return <-finish
+	})
+
+	codes := make(chan int)
+	deliver := func() {
+		codes <- postCallback(receiver, "/balanced", testCallback, nil)
+	}
+
+	// A redelivery while the first delivery is being handled is not dispatched
+	go deliver()
+	<-started
+
+	if code := postCallback(receiver, "/balanced", testCallback, nil); code != http.StatusConflict {
+		t.Fatalf("Expected concurrent duplicate to be retried later, got %v", code)
+	}
+
+	// A failed delivery releases the event for the next one
+	finish <- errors.New("database unavailable")
+	if code := <-codes; code != http.StatusInternalServerError {
+		t.Fatalf("Expected failed event to be retried, got %v", code)
+	}
+
+	go deliver()
+	<-started
+	finish <- nil
+	if code := <-codes; code != http.StatusOK {
+		t.Fatalf("Expected event to be handled, got %v", code)
+	}
+
+	if code := postCallback(receiver, "/balanced", testCallback, nil); code != http.StatusOK {
+		t.Fatalf("Expected duplicate event to be acknowledged, got %v", code)
+	}
+
+	if handled != 2 {
+		t.Fatalf("Expected the event to be dispatched twice, got %v", handled)
+	}
+}
+
+func TestCallbackReceiverHandlerPanics(t *testing.T) {
+	receiver := NewCallbackReceiver()
+
+	handled := 0
+	receiver.HandleFunc("debit.succeeded", func(ctx context.Context, event *Event) error {
+		handled++
+		if handled == 1 {
+			panic("nil map")
+		}
+		return nil
+	})
+
+	// net/http recovers panics of handlers, the receiver must let go of the
+	// event all the same
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("Expected the handler to panic")
+			}
+		}()
+		postCallback(receiver, "/balanced", testCallback, nil)
+	}()
+
+	if code := postCallback(receiver, "/balanced", testCallback, nil); code != http.StatusOK {
+		t.Fatalf("Expected redelivered event to be handled, got %v", code)
+	}
+
+	if handled != 2 {
+		t.Fatalf("Expected the event to be dispatched twice, got %v", handled)
+	}
+}
@@ -0,0 +1,97 @@
+package balanced
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nimajalali/balanced-go/balancedtest"
+)
+
+func TestCallback(t *testing.T) {
+	callback, err := CreateCallback("https://example.com/balanced/callbacks")
+	if err != nil {
+		t.Fatalf("Failed to create callback: %v", err)
+	}
+
+	if len(callback.Uri) == 0 || callback.Url != "https://example.com/balanced/callbacks" {
+		t.Fatalf("Invalid callback created: %v", callback)
+	}
+
+	retrieved, err := RetrieveCallback(callback.Uri)
+	if err != nil {
+		t.Fatalf("Failed to retrieve callback: %v", err)
+	}
+
+	if retrieved.Id != callback.Id {
+		t.Fatalf("Incorrect callback retrieved: %v", retrieved)
+	}
+
+	list, err := ListCallbacks(10, 0)
+	if err != nil {
+		t.Fatalf("Failed to list callbacks: %v", err)
+	}
+
+	if len(list.Items) == 0 || list.Items[0].Uri != callback.Uri {
+		t.Fatalf("Invalid list of callbacks: %v", list)
+	}
+
+	if err := DeleteCallback(callback.Uri); err != nil {
+		t.Fatalf("Failed to delete callback: %v", err)
+	}
+
+	_, err = RetrieveCallback(callback.Uri)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected deleted callback to be gone, got %v", err)
+	}
+
+	_, err = CreateCallback("not a url")
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("Expected an invalid url to be rejected, got %v", err)
+	}
+}
+
+func TestSyncCallbacks(t *testing.T) {
+	server := balancedtest.NewServer()
+	defer server.Close()
+
+	client := NewClient(server.URL, server.ApiKey, server.MarketplaceId)
+
+	for _, callbackUrl := range []string{"https://example.com/old",
+		"https://example.com/kept", "https://example.com/kept"} {
+		if _, err := client.CreateCallback(callbackUrl); err != nil {
+			t.Fatalf("Failed to create callback: %v", err)
+		}
+	}
+
+	changes, err := client.SyncCallbacks([]string{"https://example.com/kept",
+		"https://example.com/new"})
+	if err != nil {
+		t.Fatalf("Failed to sync callbacks: %v", err)
+	}
+
+	if len(changes.Created) != 1 || changes.Created[0].Url != "https://example.com/new" ||
+		len(changes.Deleted) != 2 {
+		t.Fatalf("Invalid changes: %+v", changes)
+	}
+
+	urls := map[string]int{}
+	for callback, err := range client.IterateCallbacks(context.Background(), nil) {
+		if err != nil {
+			t.Fatalf("Failed to list callbacks: %v", err)
+		}
+		urls[callback.Url]++
+	}
+
+	if len(urls) != 2 || urls["https://example.com/kept"] != 1 ||
+		urls["https://example.com/new"] != 1 {
+		t.Fatalf("Invalid callbacks after sync: %v", urls)
+	}
+
+	// Syncing again changes nothing
+	changes, err = client.SyncCallbacks([]string{"https://example.com/new",
+		"https://example.com/kept"})
+	if err != nil || len(changes.Created) != 0 || len(changes.Deleted) != 0 {
+		t.Fatalf("Expected no changes, got %+v, %v", changes, err)
+	}
+}
@@ -1,7 +1,9 @@
 package balanced
 
 import (
+	"context"
 	"fmt"
+	"iter"
 	"net/url"
 	"strconv"
 	"time"
@@ -35,82 +37,227 @@ type Card struct {
 	Uri             string    `json:"uri,omitempty"`
 }
 
-type ListOfCards struct {
-	FirstUri    string `json:"first_uri,omitempty"`
-	Items       []Card `json:"items,omitempty"`
-	LastUri     string `json:"last_uri,omitempty"`
-	Limit       int    `json:"limit,omitempty"`
-	NextUri     string `json:"next_uri,omitempty"`
-	Offset      int    `json:"offset,omitempty"`
-	PreviousUri string `json:"previous_uri,omitempty"`
-	Total       int    `json:"total,omitempty"`
-	Uri         string `json:"uri,omitempty"`
-}
+type ListOfCards = Page[Card]
 
-// Creates a new card
+// Creates a new card. The card details are checked with ValidateCard before
+// anything is sent.
 // WARNING PCI Compliance required to use this functionality.
+func (c *Client) TokenizeCard(expirationYear, expirationMonth int, cardNumber, securityCode,
+	name, phoneNumber, streetAddress, city, state, postalCode,
+	countryCode string, meta MetaType) (card *Card, err error) {
+
+	return c.TokenizeCardContext(context.Background(), expirationYear,
+		expirationMonth, cardNumber, securityCode, name, phoneNumber,
+		streetAddress, city, state, postalCode, countryCode, meta)
+}
+
+// TokenizeCardContext is like TokenizeCard but carries the given context.
+func (c *Client) TokenizeCardContext(ctx context.Context,
+	expirationYear, expirationMonth int, cardNumber, securityCode,
+	name, phoneNumber, streetAddress, city, state, postalCode,
+	countryCode string, meta MetaType) (card *Card, err error) {
+
+	return c.TokenizeCardWithParamsContext(ctx, &CardParams{
+		CardNumber:      cardNumber,
+		ExpirationMonth: expirationMonth,
+		ExpirationYear:  expirationYear,
+		SecurityCode:    securityCode,
+		Name:            name,
+		PhoneNumber:     phoneNumber,
+		StreetAddress:   streetAddress,
+		City:            city,
+		State:           state,
+		PostalCode:      postalCode,
+		CountryCode:     countryCode,
+		Meta:            meta,
+	})
+}
+
+// TokenizeCard is a wrapper around DefaultClient.TokenizeCard.
 func TokenizeCard(expirationYear, expirationMonth int, cardNumber, securityCode,
 	name, phoneNumber, streetAddress, city, state, postalCode,
 	countryCode string, meta MetaType) (card *Card, err error) {
 
-	// Required fields
+	return DefaultClient.TokenizeCard(expirationYear, expirationMonth,
+		cardNumber, securityCode, name, phoneNumber, streetAddress, city, state,
+		postalCode, countryCode, meta)
+}
+
+// TokenizeCardContext is a wrapper around DefaultClient.TokenizeCardContext.
+func TokenizeCardContext(ctx context.Context,
+	expirationYear, expirationMonth int, cardNumber, securityCode,
+	name, phoneNumber, streetAddress, city, state, postalCode,
+	countryCode string, meta MetaType) (card *Card, err error) {
+
+	return DefaultClient.TokenizeCardContext(ctx, expirationYear,
+		expirationMonth, cardNumber, securityCode, name, phoneNumber,
+		streetAddress, city, state, postalCode, countryCode, meta)
+}
+
+// Parameters for tokenizing a card with TokenizeCardWithParams.
+type CardParams struct {
+	// The card number, digits only. Required.
+	CardNumber string
+	// Month the card expires, 1 through 12. Required.
+	ExpirationMonth int
+	// Four digit year the card expires. Required.
+	ExpirationYear int
+	// The 3 or 4 digit code printed on the card.
+	SecurityCode string
+	// Name on the card.
+	Name          string
+	PhoneNumber   string
+	StreetAddress string
+	City          string
+	// Two letter state code, i.e. "CA".
+	State      string
+	PostalCode string
+	// ISO-3166-3 country code, i.e. "USA".
+	CountryCode string
+	Meta        MetaType
+}
+
+// Checks the parameters without contacting balanced, see ValidateCard.
+func (p *CardParams) Validate() error {
+	return ValidateCard(p.CardNumber, p.ExpirationMonth, p.ExpirationYear,
+		p.SecurityCode)
+}
+
+func (p *CardParams) payload() url.Values {
 	payload := url.Values{
-		"card_number":      {cardNumber},
-		"expiration_year":  {strconv.Itoa(expirationYear)},
-		"expiration_month": {strconv.Itoa(expirationMonth)},
+		"card_number":      {p.CardNumber},
+		"expiration_year":  {strconv.Itoa(p.ExpirationYear)},
+		"expiration_month": {strconv.Itoa(p.ExpirationMonth)},
 	}
 
-	// Add other fields, if not empty
-	addToPayload(payload, "security_code", securityCode)
-	addToPayload(payload, "name", name)
-	addToPayload(payload, "phone_number", phoneNumber)
-	addToPayload(payload, "street_address", streetAddress)
-	addToPayload(payload, "city", city)
-	addToPayload(payload, "state", state)
-	addToPayload(payload, "postal_code", postalCode)
-	addToPayload(payload, "countryCode", countryCode)
-	for key, value := range meta {
-		addToPayload(payload, "meta["+key+"]", value)
+	addToPayload(payload, "security_code", p.SecurityCode)
+	addToPayload(payload, "name", p.Name)
+	addToPayload(payload, "phone_number", p.PhoneNumber)
+	addToPayload(payload, "street_address", p.StreetAddress)
+	addToPayload(payload, "city", p.City)
+	addToPayload(payload, "state", p.State)
+	addToPayload(payload, "postal_code", p.PostalCode)
+	addToPayload(payload, "country_code", p.CountryCode)
+	addMetaToPayload(payload, p.Meta)
+
+	return payload
+}
+
+// Creates a new card from named parameters, which are validated before the
+// request is sent.
+// WARNING PCI Compliance required to use this functionality.
+func (c *Client) TokenizeCardWithParams(params *CardParams) (card *Card, err error) {
+	return c.TokenizeCardWithParamsContext(context.Background(), params)
+}
+
+// TokenizeCardWithParamsContext is like TokenizeCardWithParams but carries the given context.
+func (c *Client) TokenizeCardWithParamsContext(ctx context.Context, params *CardParams) (card *Card, err error) {
+	if params == nil {
+		params = &CardParams{}
+	}
+
+	if err = params.Validate(); err != nil {
+		return nil, err
 	}
 
-	uri := fmt.Sprintf(cardsUri, marketplaceId)
+	uri := fmt.Sprintf(cardsUri, c.MarketplaceId)
 
 	card = &Card{}
-	err = post(uri, payload, card)
+	err = c.post(ctx, uri, params.payload(), card)
 
 	return
 }
 
+// TokenizeCardWithParams is a wrapper around DefaultClient.TokenizeCardWithParams.
+func TokenizeCardWithParams(params *CardParams) (card *Card, err error) {
+	return DefaultClient.TokenizeCardWithParams(params)
+}
+
+// TokenizeCardWithParamsContext is a wrapper around DefaultClient.TokenizeCardWithParamsContext.
+func TokenizeCardWithParamsContext(ctx context.Context, params *CardParams) (card *Card, err error) {
+	return DefaultClient.TokenizeCardWithParamsContext(ctx, params)
+}
+
 // Retrieves the details of a card that has previously been created. Supply the
 // uri that was returned from your previous request, and the corresponding card
 // information will be returned. The same information is returned when creating
 // the card.
-func RetrieveCard(uri string) (card *Card, err error) {
+func (c *Client) RetrieveCard(uri string) (card *Card, err error) {
+	return c.RetrieveCardContext(context.Background(), uri)
+}
+
+// RetrieveCardContext is like RetrieveCard but carries the given context.
+func (c *Client) RetrieveCardContext(ctx context.Context, uri string) (card *Card, err error) {
 	card = &Card{}
-	err = get(uri, nil, card)
+	err = c.get(ctx, uri, nil, card)
 
 	return
 }
 
+// RetrieveCard is a wrapper around DefaultClient.RetrieveCard.
+func RetrieveCard(uri string) (card *Card, err error) {
+	return DefaultClient.RetrieveCard(uri)
+}
+
+// RetrieveCardContext is a wrapper around DefaultClient.RetrieveCardContext.
+func RetrieveCardContext(ctx context.Context, uri string) (card *Card, err error) {
+	return DefaultClient.RetrieveCardContext(ctx, uri)
+}
+
 // Returns a list of cards that you've created.
+func (c *Client) ListAllCards(limit, offset int) (*ListOfCards, error) {
+	return c.ListAllCardsContext(context.Background(), limit, offset)
+}
+
+// ListAllCardsContext is like ListAllCards but carries the given context.
+func (c *Client) ListAllCardsContext(ctx context.Context, limit, offset int) (*ListOfCards, error) {
+	uri := fmt.Sprintf(cardsUri, c.MarketplaceId)
+
+	return c.ListAllCardsForUriContext(ctx, limit, offset, uri)
+}
+
+// ListAllCards is a wrapper around DefaultClient.ListAllCards.
 func ListAllCards(limit, offset int) (*ListOfCards, error) {
-	uri := fmt.Sprintf(cardsUri, marketplaceId)
+	return DefaultClient.ListAllCards(limit, offset)
+}
 
-	return ListAllCardsForUri(limit, offset, uri)
+// ListAllCardsContext is a wrapper around DefaultClient.ListAllCardsContext.
+func ListAllCardsContext(ctx context.Context, limit, offset int) (*ListOfCards, error) {
+	return DefaultClient.ListAllCardsContext(ctx, limit, offset)
 }
 
 // Returns a list of cards for a given uri
-func ListAllCardsForUri(limit, offset int, uri string) (listOfCards *ListOfCards, err error) {
+func (c *Client) ListAllCardsForUri(limit, offset int, uri string) (listOfCards *ListOfCards, err error) {
+	return c.ListAllCardsForUriContext(context.Background(), limit, offset, uri)
+}
+
+// ListAllCardsForUriContext is like ListAllCardsForUri but carries the given context.
+func (c *Client) ListAllCardsForUriContext(ctx context.Context, limit, offset int, uri string) (listOfCards *ListOfCards, err error) {
 	payload := defaultPayload(limit, offset)
 
 	listOfCards = &ListOfCards{}
-	err = get(uri, payload, listOfCards)
+	err = c.get(ctx, uri, payload, listOfCards)
 
 	return
 }
 
+// ListAllCardsForUri is a wrapper around DefaultClient.ListAllCardsForUri.
+func ListAllCardsForUri(limit, offset int, uri string) (listOfCards *ListOfCards, err error) {
+	return DefaultClient.ListAllCardsForUri(limit, offset, uri)
+}
+
+// ListAllCardsForUriContext is a wrapper around DefaultClient.ListAllCardsForUriContext.
+func ListAllCardsForUriContext(ctx context.Context, limit, offset int, uri string) (listOfCards *ListOfCards, err error) {
+	return DefaultClient.ListAllCardsForUriContext(ctx, limit, offset, uri)
+}
+
 // Update information in a card
-func UpdateCard(uri string, meta MetaType) (card *Card, err error) {
+func (c *Client) UpdateCard(uri string, meta MetaType) (card *Card, err error) {
+	return c.UpdateCardContext(context.Background(), uri, meta)
+}
+
+// UpdateCardContext is like UpdateCard but carries the given context.
+func (c *Client) UpdateCardContext(ctx context.Context, uri string, meta MetaType) (card *Card, err error) {
 	payload := url.Values{}
 
 	for key, value := range meta {
@@ -118,19 +265,66 @@ func UpdateCard(uri string, meta MetaType) (card *Card, err error) {
 	}
 
 	card = &Card{}
-	err = put(uri, payload, card)
+	err = c.put(ctx, uri, payload, card)
 
 	return
 }
 
+// UpdateCard is a wrapper around DefaultClient.UpdateCard.
+func UpdateCard(uri string, meta MetaType) (card *Card, err error) {
+	return DefaultClient.UpdateCard(uri, meta)
+}
+
+// UpdateCardContext is a wrapper around DefaultClient.UpdateCardContext.
+func UpdateCardContext(ctx context.Context, uri string, meta MetaType) (card *Card, err error) {
+	return DefaultClient.UpdateCardContext(ctx, uri, meta)
+}
+
 // Invalidating a card will mark the card as invalid, so it may not be charged.
-func InvalidateCard(uri string) (card *Card, err error) {
+func (c *Client) InvalidateCard(uri string) (card *Card, err error) {
+	return c.InvalidateCardContext(context.Background(), uri)
+}
+
+// InvalidateCardContext is like InvalidateCard but carries the given context.
+func (c *Client) InvalidateCardContext(ctx context.Context, uri string) (card *Card, err error) {
 	payload := url.Values{
 		"is_valid": {"false"},
 	}
 
 	card = &Card{}
-	err = put(uri, payload, card)
+	err = c.put(ctx, uri, payload, card)
 
 	return
 }
+
+// InvalidateCard is a wrapper around DefaultClient.InvalidateCard.
+func InvalidateCard(uri string) (card *Card, err error) {
+	return DefaultClient.InvalidateCard(uri)
+}
+
+// InvalidateCardContext is a wrapper around DefaultClient.InvalidateCardContext.
+func InvalidateCardContext(ctx context.Context, uri string) (card *Card, err error) {
+	return DefaultClient.InvalidateCardContext(ctx, uri)
+}
+
+// Iterates over every card that you've created.
+func (c *Client) IterateCards(ctx context.Context, options *IterOptions) iter.Seq2[Card, error] {
+	uri := fmt.Sprintf(cardsUri, c.MarketplaceId)
+
+	return Iterate[Card](ctx, c, uri, options)
+}
+
+// IterateCards is a wrapper around DefaultClient.IterateCards.
+func IterateCards(ctx context.Context, options *IterOptions) iter.Seq2[Card, error] {
+	return DefaultClient.IterateCards(ctx, options)
+}
+
+// Iterates over every card for a given uri
+func (c *Client) IterateCardsForUri(ctx context.Context, uri string, options *IterOptions) iter.Seq2[Card, error] {
+	return Iterate[Card](ctx, c, uri, options)
+}
+
+// IterateCardsForUri is a wrapper around DefaultClient.IterateCardsForUri.
+func IterateCardsForUri(ctx context.Context, uri string, options *IterOptions) iter.Seq2[Card, error] {
+	return DefaultClient.IterateCardsForUri(ctx, uri, options)
+}
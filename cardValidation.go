@@ -0,0 +1,214 @@
+package balanced
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	CardBrandVisa            = "Visa"
+	CardBrandMasterCard      = "MasterCard"
+	CardBrandAmericanExpress = "American Express"
+	CardBrandDiscover        = "Discover"
+	CardBrandJCB             = "JCB"
+	CardBrandDinersClub      = "Diners Club"
+)
+
+// Numbering rules of a card brand.
+type cardBrandRule struct {
+	brand string
+	// Ranges of leading digits, inclusive, i.e. {"51", "55"}. Both ends have
+	// the same number of digits.
+	prefixes [][2]string
+	lengths  []int
+	// Number of digits in the security code.
+	securityCodeLength int
+}
+
+var cardBrandRules = []cardBrandRule{
+	{
+		brand:              CardBrandVisa,
+		prefixes:           [][2]string{{"4", "4"}},
+		lengths:            []int{13, 16, 19},
+		securityCodeLength: 3,
+	},
+	{
+		brand:              CardBrandMasterCard,
+		prefixes:           [][2]string{{"51", "55"}, {"2221", "2720"}},
+		lengths:            []int{16},
+		securityCodeLength: 3,
+	},
+	{
+		brand:              CardBrandAmericanExpress,
+		prefixes:           [][2]string{{"34", "34"}, {"37", "37"}},
+		lengths:            []int{15},
+		securityCodeLength: 4,
+	},
+	{
+		brand:              CardBrandDiscover,
+		prefixes:           [][2]string{{"6011", "6011"}, {"644", "649"}, {"65", "65"}},
+		lengths:            []int{16, 17, 18, 19},
+		securityCodeLength: 3,
+	},
+	{
+		brand:              CardBrandJCB,
+		prefixes:           [][2]string{{"3528", "3589"}},
+		lengths:            []int{16, 17, 18, 19},
+		securityCodeLength: 3,
+	},
+	{
+		brand:              CardBrandDinersClub,
+		prefixes:           [][2]string{{"300", "305"}, {"36", "36"}, {"38", "39"}},
+		lengths:            []int{14, 15, 16, 17, 18, 19},
+		securityCodeLength: 3,
+	},
+}
+
+const (
+	// Length bounds for numbers of unknown brands.
+	minCardNumberLength = 12
+	maxCardNumberLength = 19
+)
+
+// Returns the numbering rules for the brand a card number belongs to, or nil
+// if the brand is unknown.
+func cardBrandRuleFor(number string) *cardBrandRule {
+	for i := range cardBrandRules {
+		rule := &cardBrandRules[i]
+		for _, prefix := range rule.prefixes {
+			if len(number) < len(prefix[0]) {
+				continue
+			}
+
+			leading := number[:len(prefix[0])]
+			if leading >= prefix[0] && leading <= prefix[1] {
+				return rule
+			}
+		}
+	}
+
+	return nil
+}
+
+// Returns the brand of a card number, i.e. CardBrandVisa, or an empty string
+// if the brand is unknown.
+func CardBrand(cardNumber string) string {
+	if rule := cardBrandRuleFor(cardNumber); rule != nil {
+		return rule.brand
+	}
+
+	return ""
+}
+
+// Checks card details the way balanced would, without contacting it: the
+// number must pass the Luhn checksum and match the length of its brand, the
+// card must not have expired and the security code, if given, must have as
+// many digits as its brand uses. Returns a *ValidationError listing every
+// invalid field.
+func ValidateCard(cardNumber string, expirationMonth, expirationYear int, securityCode string) error {
+	return validateCard(cardNumber, expirationMonth, expirationYear,
+		securityCode, time.Now())
+}
+
+func validateCard(cardNumber string, expirationMonth, expirationYear int, securityCode string, now time.Time) error {
+	v := &ValidationError{}
+
+	rule := cardBrandRuleFor(cardNumber)
+
+	switch {
+	case len(cardNumber) == 0:
+		v.add("card_number", "is required")
+	case !isDigits(cardNumber):
+		v.add("card_number", "must only contain digits")
+	case rule != nil && !slices.Contains(rule.lengths, len(cardNumber)):
+		v.add("card_number", "must be %v digits long for %v cards, got %v",
+			joinInts(rule.lengths), rule.brand, len(cardNumber))
+	case len(cardNumber) < minCardNumberLength || len(cardNumber) > maxCardNumberLength:
+		v.add("card_number", "must be between %v and %v digits long, got %v",
+			minCardNumberLength, maxCardNumberLength, len(cardNumber))
+	case !luhnValid(cardNumber):
+		v.add("card_number", "is not a valid card number")
+	}
+
+	validMonth := expirationMonth >= 1 && expirationMonth <= 12
+	if !validMonth {
+		v.add("expiration_month", "must be between 1 and 12, got %v",
+			expirationMonth)
+	}
+
+	validYear := expirationYear >= 1000 && expirationYear <= 9999
+	if !validYear {
+		v.add("expiration_year", "must be a four digit year, got %v",
+			expirationYear)
+	}
+
+	// Cards expire at the end of their expiration month
+	if validMonth && validYear && (expirationYear < now.Year() ||
+		(expirationYear == now.Year() && time.Month(expirationMonth) < now.Month())) {
+		v.add("expiration_year", "card expired in %02d/%v", expirationMonth,
+			expirationYear)
+	}
+
+	if len(securityCode) != 0 {
+		switch {
+		case !isDigits(securityCode):
+			v.add("security_code", "must only contain digits")
+		case rule != nil && len(securityCode) != rule.securityCodeLength:
+			v.add("security_code", "must be %v digits long for %v cards, got %v",
+				rule.securityCodeLength, rule.brand, len(securityCode))
+		case len(securityCode) < 3 || len(securityCode) > 4:
+			v.add("security_code", "must be 3 or 4 digits long, got %v",
+				len(securityCode))
+		}
+	}
+
+	return v.err()
+}
+
+// Reports whether a string of digits passes the Luhn checksum used by card
+// numbers.
+func luhnValid(number string) bool {
+	if len(number) == 0 {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		digit := int(number[i] - '0')
+		if digit < 0 || digit > 9 {
+			return false
+		}
+
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+
+	return sum%10 == 0
+}
+
+func isDigits(s string) bool {
+	return len(s) != 0 && strings.Trim(s, "0123456789") == ""
+}
+
+// Formats lengths as "13, 16 or 19".
+func joinInts(values []int) string {
+	s := make([]string, len(values))
+	for i, value := range values {
+		s[i] = strconv.Itoa(value)
+	}
+
+	if len(s) == 1 {
+		return s[0]
+	}
+
+	return strings.Join(s[:len(s)-1], ", ") + " or " + s[len(s)-1]
+}
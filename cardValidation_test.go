@@ -0,0 +1,107 @@
+package balanced
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCardBrand(t *testing.T) {
+	brands := map[string]string{
+		testVisaCard:       CardBrandVisa,
+		testAmexCard:       CardBrandAmericanExpress,
+		testMasterCard:     CardBrandMasterCard,
+		"2223003122003222": CardBrandMasterCard,
+		"6011000990139424": CardBrandDiscover,
+		"3530111333300000": CardBrandJCB,
+		"30569309025904":   CardBrandDinersClub,
+		"9999999999999995": "",
+	}
+
+	for number, brand := range brands {
+		if CardBrand(number) != brand {
+			t.Errorf("Expected %v to be %q, got %q", number, brand,
+				CardBrand(number))
+		}
+	}
+}
+
+func TestValidateCard(t *testing.T) {
+	now := time.Date(2026, time.June, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		number       string
+		month, year  int
+		securityCode string
+		// Invalid fields, in order
+		fields []string
+	}{
+		{testVisaCard, 12, 2030, testVisaSecCode, nil},
+		{testAmexCard, 6, 2026, testAmexSecCode, nil},
+		{testMasterCard, 1, 2027, "", nil},
+		{"9999999999999995", 1, 2027, "1234", nil},
+		{"", 1, 2027, "", []string{"card_number"}},
+		{"4111-1111-1111-1111", 1, 2027, "", []string{"card_number"}},
+		{"4111111111111112", 1, 2027, "", []string{"card_number"}},
+		{"411111111111111", 1, 2027, "", []string{"card_number"}},
+		{"12345", 1, 2027, "", []string{"card_number"}},
+		{testVisaCard, 5, 2026, "", []string{"expiration_year"}},
+		{testVisaCard, 12, 2025, "", []string{"expiration_year"}},
+		{testVisaCard, 0, 30, "", []string{"expiration_month", "expiration_year"}},
+		{testVisaCard, 1, 2027, testAmexSecCode, []string{"security_code"}},
+		{testAmexCard, 1, 2027, testVisaSecCode, []string{"security_code"}},
+		{testVisaCard, 1, 2027, "12a", []string{"security_code"}},
+		{"4111111111111112", 13, 2027, "12", []string{"card_number",
+			"expiration_month", "security_code"}},
+	}
+
+	for _, test := range tests {
+		err := validateCard(test.number, test.month, test.year,
+			test.securityCode, now)
+
+		if len(test.fields) == 0 {
+			if err != nil {
+				t.Errorf("Expected %v to be valid, got %v", test.number, err)
+			}
+			continue
+		}
+
+		var validationError *ValidationError
+		if !errors.As(err, &validationError) || !errors.Is(err, ErrValidation) {
+			t.Errorf("Expected a validation error for %v, got %v", test.number, err)
+			continue
+		}
+
+		fields := []string{}
+		for _, fieldError := range validationError.Errors {
+			fields = append(fields, fieldError.Field)
+		}
+
+		if len(fields) != len(test.fields) {
+			t.Errorf("Expected invalid fields %v for %v, got %v", test.fields,
+				test.number, fields)
+			continue
+		}
+		for i := range fields {
+			if fields[i] != test.fields[i] {
+				t.Errorf("Expected invalid fields %v for %v, got %v",
+					test.fields, test.number, fields)
+				break
+			}
+		}
+	}
+}
+
+func TestTokenizeCardValidation(t *testing.T) {
+	// Invalid cards never reach balanced
+	client := newStaticClient(http.StatusInternalServerError, "")
+
+	_, err := client.TokenizeCard(2020, 12, "4111111111111112", "", "", "", "",
+		"", "", "", "", nil)
+
+	var validationError *ValidationError
+	if !errors.As(err, &validationError) || len(validationError.Errors) != 2 {
+		t.Fatalf("Expected an invalid number and expiration, got %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package balanced
 
 import (
 	"testing"
+	"time"
 )
 
 const (
@@ -15,6 +16,9 @@ const (
 	testInsufficientFunds = "4444444444444448"
 )
 
+// Expiration year of the test cards, far enough ahead that they never expire.
+var testExpirationYear = time.Now().Year() + 5
+
 func TestCard(t *testing.T) {
 	card := tokenizeCard(t)
 	card = retrieveCard(t, card)
@@ -25,7 +29,7 @@ func TestCard(t *testing.T) {
 }
 
 func tokenizeCard(t *testing.T) *Card {
-	card, err := TokenizeCard(2020, 12, testVisaCard, testVisaSecCode,
+	card, err := TokenizeCard(testExpirationYear, 12, testVisaCard, testVisaSecCode,
 		"Peter Sherman", "1234567890", "42 Wallaby Way", "Sydney", "CA", "92617",
 		"US", nil)
 
@@ -0,0 +1,286 @@
+package balanced
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	redacted = "[REDACTED]"
+)
+
+// Returned by a replaying cassette for a request it has no recording of.
+var ErrNoInteraction = errors.New("Balanced API: No recorded interaction for request")
+
+// Whether a cassette records interactions or replays them.
+type CassetteMode int
+
+const (
+	// Answer requests from recorded interactions, never touching the network.
+	CassetteReplay CassetteMode = iota
+	// Send requests and record every interaction.
+	CassetteRecord
+)
+
+// A recorded request and the response balanced answered it with.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Method  string      `json:"method"`
+	Path    string      `json:"path"`
+	Payload string      `json:"payload,omitempty"`
+	Header  http.Header `json:"header,omitempty"`
+}
+
+// Json bodies are kept in Body so cassettes stay readable, anything else in
+// RawBody.
+type RecordedResponse struct {
+	StatusCode int             `json:"status_code"`
+	Header     http.Header     `json:"header,omitempty"`
+	Body       json.RawMessage `json:"body,omitempty"`
+	RawBody    string          `json:"raw_body,omitempty"`
+}
+
+// A Cassette records api interactions to a file and replays them, so tests can
+// pin the exact payloads balanced sends without a live marketplace. Requests
+// are matched on method, path and form payload. The api key, card numbers,
+// security codes and bank account numbers are scrubbed before anything is
+// recorded.
+//
+//	cassette, err := balanced.LoadCassette("testdata/debits.json")
+//	...
+//	client.Use(cassette.Middleware())
+type Cassette struct {
+	Path         string
+	Mode         CassetteMode
+	Interactions []*Interaction
+
+	mu sync.Mutex
+	// Number of times each interaction was replayed
+	replayed []int
+}
+
+// Loads a cassette from a file, for replaying.
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Balanced API: Unable to read cassette: %w", err)
+	}
+
+	cassette := &Cassette{Path: path, Mode: CassetteReplay}
+	if err := json.Unmarshal(data, &cassette.Interactions); err != nil {
+		return nil, fmt.Errorf("Balanced API: Unable to parse cassette %v: %w", path, err)
+	}
+
+	return cassette, nil
+}
+
+// Creates an empty cassette that records to the given file. Call Save once
+// done.
+func NewCassetteRecorder(path string) *Cassette {
+	return &Cassette{Path: path, Mode: CassetteRecord}
+}
+
+// Writes the recorded interactions to the cassette's file.
+func (c *Cassette) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := json.MarshalIndent(c.Interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(c.Path, append(data, '\n'), 0644)
+}
+
+// Returns middleware that records or replays requests, depending on Mode.
+func (c *Cassette) Middleware() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			if c.Mode == CassetteReplay {
+				return c.replay(req)
+			}
+
+			resp, err := next(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+
+			c.record(req, resp)
+
+			return resp, nil
+		}
+	}
+}
+
+func (c *Cassette) record(req *Request, resp *Response) {
+	key := apiKeyFromHeader(req.Header)
+
+	header := http.Header{}
+	for name, values := range req.Header {
+		header[name] = values
+	}
+	if len(header.Get("Authorization")) != 0 {
+		header.Set("Authorization", redacted)
+	}
+
+	recorded := RecordedResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+	}
+	body := scrubBody(string(resp.Body), key)
+	if json.Valid([]byte(body)) {
+		recorded.Body = json.RawMessage(body)
+	} else {
+		recorded.RawBody = body
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.Interactions = append(c.Interactions, &Interaction{
+		Request: RecordedRequest{
+			Method:  req.Method,
+			Path:    req.Path,
+			Payload: scrubPayload(req.Payload),
+			Header:  header,
+		},
+		Response: recorded,
+	})
+}
+
+// Answers a request with the first recorded interaction that matches it and
+// has not been replayed yet. Once every match was replayed the last one keeps
+// answering, so polling a resource works.
+func (c *Cassette) replay(req *Request) (*Response, error) {
+	payload := scrubPayload(req.Payload)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.replayed) != len(c.Interactions) {
+		c.replayed = make([]int, len(c.Interactions))
+	}
+
+	match := -1
+	for i, interaction := range c.Interactions {
+		recorded := interaction.Request
+		if recorded.Method != req.Method || recorded.Path != req.Path ||
+			recorded.Payload != payload {
+			continue
+		}
+
+		match = i
+		if c.replayed[i] == 0 {
+			break
+		}
+	}
+
+	if match == -1 {
+		return nil, fmt.Errorf("%w: %v %v %v", ErrNoInteraction, req.Method,
+			req.Path, payload)
+	}
+
+	c.replayed[match]++
+	recorded := c.Interactions[match].Response
+
+	body := []byte(recorded.RawBody)
+	if len(recorded.Body) != 0 {
+		body = recorded.Body
+	}
+
+	return &Response{
+		StatusCode: recorded.StatusCode,
+		Header:     recorded.Header,
+		Body:       body,
+	}, nil
+}
+
+// Returns the api key sent in a basic auth header.
+func apiKeyFromHeader(header http.Header) string {
+	auth := header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Basic ") {
+		return ""
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(auth, "Basic "))
+	if err != nil {
+		return ""
+	}
+
+	key, _, _ := strings.Cut(string(decoded), ":")
+
+	return key
+}
+
+// Payload fields that are never recorded. Card security codes must not be
+// stored at all, and bank account numbers are not Luhn checked so they would
+// slip past scrubCardNumbers.
+var sensitivePayloadKeys = map[string]bool{
+	"security_code":                true,
+	"card[security_code]":          true,
+	"account_number":               true,
+	"bank_account[account_number]": true,
+}
+
+// Encodes a payload with card numbers masked and sensitive fields redacted.
+func scrubPayload(payload url.Values) string {
+	if len(payload) == 0 {
+		return ""
+	}
+
+	scrubbed := url.Values{}
+	for key, values := range payload {
+		for _, value := range values {
+			if sensitivePayloadKeys[key] {
+				scrubbed.Add(key, redacted)
+				continue
+			}
+
+			scrubbed.Add(key, scrubCardNumbers(value))
+		}
+	}
+
+	return scrubbed.Encode()
+}
+
+var (
+	digitRunPattern = regexp.MustCompile(`\b\d{12,19}\b`)
+	secretPattern   = regexp.MustCompile(`("(?:secret|security_code)"\s*:\s*)"[^"]*"`)
+)
+
+// Masks card numbers, the api key, api key secrets and security codes in a
+// response body.
+func scrubBody(body, key string) string {
+	if len(key) != 0 {
+		body = strings.ReplaceAll(body, key, redacted)
+	}
+	body = secretPattern.ReplaceAllString(body, `$1"`+redacted+`"`)
+
+	return scrubCardNumbers(body)
+}
+
+// Masks every run of digits that looks like a card number, keeping the last
+// four digits.
+func scrubCardNumbers(s string) string {
+	return digitRunPattern.ReplaceAllStringFunc(s, func(digits string) string {
+		if !luhnValid(digits) {
+			return digits
+		}
+
+		return strings.Repeat("x", len(digits)-4) + digits[len(digits)-4:]
+	})
+}
@@ -0,0 +1,190 @@
+package balanced
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nimajalali/balanced-go/balancedtest"
+)
+
+const (
+	cassetteMarketplaceUri = "/v1/marketplaces/TEST-MP0000000000000000000004"
+	cassetteDebitUri       = cassetteMarketplaceUri + "/debits/WD0000000000000000000015"
+	cassetteHoldUri        = cassetteMarketplaceUri + "/holds/HL0000000000000000000012"
+)
+
+// Creates a client that replays the given cassette.
+func newCassetteClient(t *testing.T, path string) *Client {
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("Failed to load cassette: %v", err)
+	}
+
+	client := NewClient("http://balanced.invalid", "ak-test-key", "TEST-MP0000000000000000000004")
+	client.Use(cassette.Middleware())
+
+	return client
+}
+
+// Replays a cassette recorded against balancedtest, checking that what the fake
+// serves round-trips through a cassette and decodes. It says nothing about the
+// payloads of the live api.
+func TestCassetteFakeServerRoundTrip(t *testing.T) {
+	client := newCassetteClient(t, "testdata/cassettes/fakeserver_debit_hold_event.json")
+
+	debit, err := client.RetrieveDebit(cassetteDebitUri)
+	if err != nil {
+		t.Fatalf("Failed to retrieve debit: %v", err)
+	}
+
+	if debit.Amount != 5000 || debit.Status != "succeeded" ||
+		debit.Description != "Order #42" ||
+		debit.AppearsOnStatementAs != "TEST ORDER" ||
+		debit.Hold.Uri != cassetteHoldUri ||
+		debit.Source.Card() == nil || debit.Source.Card().LastFour != "1111" ||
+		debit.Source.Card().Brand != "Visa" ||
+		debit.CreatedAt.IsZero() || len(debit.RefundsUri) == 0 {
+		t.Fatalf("Invalid debit decoded: %+v", debit)
+	}
+
+	hold, err := client.RetrieveHold(cassetteHoldUri)
+	if err != nil {
+		t.Fatalf("Failed to retrieve hold: %v", err)
+	}
+
+	if hold.Amount != 5000 || hold.Meta["order_id"] != "42" || hold.IsVoid ||
+		hold.Source.Card() == nil || hold.Source.Card().ExpirationYear != 2030 ||
+		!hold.ExpiresAt.After(hold.CreatedAt) {
+		t.Fatalf("Invalid hold decoded: %+v", hold)
+	}
+
+	events, err := client.ListAllEvents(2, 0)
+	if err != nil {
+		t.Fatalf("Failed to list events: %v", err)
+	}
+
+	if len(events.Items) != 2 || events.Items[0].Type != "debit.succeeded" ||
+		events.Items[0].OccurredAt.IsZero() || len(events.NextUri) == 0 ||
+		events.Items[0].Entity.Debit() == nil ||
+		events.Items[0].Entity.Debit().Uri != cassetteDebitUri {
+		t.Fatalf("Invalid events decoded: %+v", events)
+	}
+
+	// Requests that were not recorded fail
+	_, err = client.RetrieveDebit(cassetteMarketplaceUri + "/debits/WD1")
+	if !errors.Is(err, ErrNoInteraction) {
+		t.Fatalf("Expected a missing interaction, got %v", err)
+	}
+}
+
+func TestCassetteRecord(t *testing.T) {
+	server := balancedtest.NewServer()
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cards.json")
+	recorder := NewCassetteRecorder(path)
+
+	client := NewClient(server.URL, server.ApiKey, server.MarketplaceId)
+	client.Use(recorder.Middleware())
+
+	recordedCard, err := client.TokenizeCard(testExpirationYear, 12, testVisaCard, testVisaSecCode,
+		"Peter Sherman", "", "", "", "", "", "", nil)
+	if err != nil {
+		t.Fatalf("Failed to tokenize card: %v", err)
+	}
+
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Failed to save cassette: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(data), server.ApiKey) ||
+		strings.Contains(string(data), testVisaCard) {
+		t.Fatalf("Cassette was not scrubbed: %s", data)
+	}
+
+	// Replaying the same request answers with the recorded card
+	client = newCassetteClient(t, path)
+
+	card, err := client.TokenizeCard(testExpirationYear, 12, testVisaCard, testVisaSecCode,
+		"Peter Sherman", "", "", "", "", "", "", nil)
+	if err != nil {
+		t.Fatalf("Failed to replay card: %v", err)
+	}
+
+	if card.Uri != recordedCard.Uri || card.LastFour != "1111" {
+		t.Fatalf("Invalid card replayed: %v", card)
+	}
+}
+
+func TestCassetteRecordScrubsSensitiveFields(t *testing.T) {
+	server := balancedtest.NewServer()
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "sensitive.json")
+	recorder := NewCassetteRecorder(path)
+
+	client := NewClient(server.URL, server.ApiKey, server.MarketplaceId)
+	client.Use(recorder.Middleware())
+
+	_, err := client.TokenizeCard(testExpirationYear, 12, testVisaCard, testVisaSecCode,
+		"Peter Sherman", "", "", "", "", "", "", nil)
+	if err != nil {
+		t.Fatalf("Failed to tokenize card: %v", err)
+	}
+
+	_, err = client.CreateNewBankAccount("Johann Bernoulli", "9900000001",
+		"121000358", BankAccountTypeChecking)
+	if err != nil {
+		t.Fatalf("Failed to create bank account: %v", err)
+	}
+
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Failed to save cassette: %v", err)
+	}
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("Failed to load cassette: %v", err)
+	}
+
+	for _, interaction := range cassette.Interactions {
+		payload, err := url.ParseQuery(interaction.Request.Payload)
+		if err != nil {
+			t.Fatalf("Invalid recorded payload: %v", err)
+		}
+
+		for _, key := range []string{"security_code", "account_number"} {
+			if value, ok := payload[key]; ok && value[0] != redacted {
+				t.Fatalf("Recorded %v was not redacted: %v", key, value)
+			}
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(data), "security_code="+testVisaSecCode) ||
+		strings.Contains(string(data), "9900000001") {
+		t.Fatalf("Cassette was not scrubbed: %s", data)
+	}
+
+	// Replaying matches on the scrubbed payload
+	client = newCassetteClient(t, path)
+
+	_, err = client.TokenizeCard(testExpirationYear, 12, testVisaCard, "999",
+		"Peter Sherman", "", "", "", "", "", "", nil)
+	if err != nil {
+		t.Fatalf("Failed to replay card: %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package balanced
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// The last event an EventPoller handled. A checkpoint without an EventId marks
+// when a poller that skips history first polled, and the poller delivers
+// every event that occurred since.
+type Checkpoint struct {
+	EventId    string    `json:"event_id"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// Persists an EventPoller's checkpoint, so that it resumes where it left off
+// after a restart.
+type CheckpointStore interface {
+	// Returns the saved checkpoint, or nil if none was saved yet.
+	LoadCheckpoint(ctx context.Context) (*Checkpoint, error)
+	SaveCheckpoint(ctx context.Context, checkpoint *Checkpoint) error
+}
+
+// Keeps the checkpoint in a json file. The file is replaced atomically, so a
+// crash while saving leaves the previous checkpoint in place.
+type FileCheckpointStore struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+// Creates a store that keeps the checkpoint in the file at path. The file is
+// created on the first save.
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{Path: path}
+}
+
+func (s *FileCheckpointStore) LoadCheckpoint(ctx context.Context) (*Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Balanced API: Unable to read checkpoint: %w", err)
+	}
+
+	checkpoint := &Checkpoint{}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("Balanced API: Unable to parse checkpoint %v: %w", s.Path, err)
+	}
+
+	return checkpoint, nil
+}
+
+func (s *FileCheckpointStore) SaveCheckpoint(ctx context.Context, checkpoint *Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".*")
+	if err != nil {
+		return fmt.Errorf("Balanced API: Unable to save checkpoint: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(append(data, '\n'))
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), s.Path)
+	}
+	if err != nil {
+		return fmt.Errorf("Balanced API: Unable to save checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// Returns the checkpoint for a handled event.
+func eventCheckpoint(event *Event) *Checkpoint {
+	return &Checkpoint{EventId: event.Id, OccurredAt: event.OccurredAt}
+}
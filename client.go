@@ -2,6 +2,8 @@ package balanced
 
 import (
 	"bytes"
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,6 +12,8 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 const (
@@ -19,45 +23,97 @@ const (
 	contentType  = "application/x-www-form-urlencoded"
 )
 
-func get(path string, payload url.Values, out interface{}) error {
-	return request("GET", path, payload, out)
-}
+// A Client holds everything needed to talk to a single Balanced marketplace.
+// Every resource operation is available as a method on Client; the package
+// level functions are thin wrappers around DefaultClient.
+type Client struct {
+	ApiRoot       string
+	MarketplaceId string
+
+	// ApiKey is the key requests are sent with. Once the client is in use,
+	// change it with SetApiKey or RotateApiKey instead of assigning it.
+	ApiKey string
+
+	// HTTPClient is used to send requests. When nil http.DefaultClient is
+	// used. Set it to control timeouts, proxies, TLS configuration or the
+	// underlying transport.
+	HTTPClient *http.Client
+
+	// Middleware is applied to every request, in order. The first middleware
+	// is the outermost one and sees the request first and the response last.
+	Middleware []Middleware
+
+	// RetryPolicy controls retries of failed requests. When nil requests are
+	// never retried.
+	RetryPolicy *RetryPolicy
 
-func post(path string, payload url.Values, out interface{}) error {
-	return request("POST", path, payload, out)
+	// BankDirectory resolves bank names for routing numbers, see
+	// LookupBankName. When nil the directory bundled with the package is used.
+	BankDirectory *BankDirectory
+
+	// Guards ApiKey during a key rotation, along with the previous key that
+	// is still used as a fallback until previousKeyUntil. See RotateApiKey.
+	keyMu            sync.Mutex
+	previousKey      string
+	previousKeyUntil time.Time
 }
 
-func put(path string, payload url.Values, out interface{}) error {
-	return request("PUT", path, payload, out)
+// Creates a new client for the given api root, api key and marketplace id.
+func NewClient(root, key, marketId string) *Client {
+	return &Client{
+		ApiRoot:       root,
+		ApiKey:        key,
+		MarketplaceId: marketId,
+	}
 }
 
-func delete(path string, payload url.Values, out interface{}) error {
-	return request("DELETE", path, payload, out)
+// Replaces the transport used to send requests, keeping any other settings of
+// HTTPClient.
+func (c *Client) SetTransport(transport http.RoundTripper) {
+	httpClient := &http.Client{}
+	if c.HTTPClient != nil {
+		*httpClient = *c.HTTPClient
+	}
+	httpClient.Transport = transport
+
+	c.HTTPClient = httpClient
 }
 
-func request(method, path string, payload url.Values, out interface{}) error {
-	// Build Uri
-	var uri bytes.Buffer
-	uri.WriteString(apiRoot)
-	uri.WriteString(path)
+// Appends middleware to the client's middleware chain.
+func (c *Client) Use(middleware ...Middleware) {
+	c.Middleware = append(c.Middleware, middleware...)
+}
 
-	// Build Body
-	var body io.Reader
-	if payload != nil && len(payload) != 0 {
-		if method == "GET" {
-			// GET request encode payload in uri
-			uri.WriteString("?")
-			uri.WriteString(payload.Encode())
-		} else {
-			// Not a GET request, encode payload into body of request
-			body = strings.NewReader(payload.Encode())
-		}
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
 	}
 
-	// Build Request
-	req, err := http.NewRequest(method, uri.String(), body)
-	if err != nil {
-		return fmt.Errorf("Balanced API: Error creating %v request %g", method, err)
+	return http.DefaultClient
+}
+
+func (c *Client) get(ctx context.Context, path string, payload url.Values, out interface{}) error {
+	return c.request(ctx, "GET", path, payload, out)
+}
+
+func (c *Client) post(ctx context.Context, path string, payload url.Values, out interface{}) error {
+	return c.request(ctx, "POST", path, payload, out)
+}
+
+func (c *Client) put(ctx context.Context, path string, payload url.Values, out interface{}) error {
+	return c.request(ctx, "PUT", path, payload, out)
+}
+
+func (c *Client) delete(ctx context.Context, path string, payload url.Values, out interface{}) error {
+	return c.request(ctx, "DELETE", path, payload, out)
+}
+
+func (c *Client) request(ctx context.Context, method, path string, payload url.Values, out interface{}) error {
+	req := &Request{
+		Method:  method,
+		Path:    path,
+		Payload: payload,
+		Header:  http.Header{},
 	}
 
 	// Add Headers
@@ -68,38 +124,130 @@ func request(method, path string, payload url.Values, out interface{}) error {
 	// Add Basic Authentication
 	// Balanced does not have a traditional username and password. Just a key
 	// that's passed in as username, password is left empty.
-	req.SetBasicAuth(apiKey, "")
+	key, previousKey := c.apiKeys()
+	req.Header.Set("Authorization", basicAuth(key))
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("Balanced API: Error sending %v request %g", method, err)
+	addIdempotencyKey(ctx, req)
+
+	handler := c.handler()
+	if len(previousKey) != 0 {
+		handler = withPreviousKey(handler, previousKey)
 	}
-	defer resp.Body.Close()
+	policy := c.RetryPolicy
 
-	respBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("Balanced API: Error reading response bytes %g", err)
+	for attempt := 1; ; attempt++ {
+		resp, err := handler(ctx, req)
+		if err == nil {
+			err = decodeResponse(resp, out)
+		}
+
+		if err == nil {
+			return nil
+		}
+
+		if policy == nil {
+			return err
+		}
+
+		if attempt >= policy.MaxAttempts || !isRepeatable(req) ||
+			!isTransient(resp, err) || ctx.Err() != nil {
+			return &RetryError{Attempts: attempt, Err: err}
+		}
+
+		if sleep(ctx, policy.backoff(attempt)) != nil {
+			return &RetryError{Attempts: attempt, Err: err}
+		}
 	}
+}
+
+// Decodes a raw response into out, or into an error if balanced responded with
+// one.
+func decodeResponse(resp *Response, out interface{}) error {
+	// Balanced responds to failed requests with an error status and a json
+	// description of the error. Resources such as debits carry a status of
+	// their own, so the http status is what tells errors apart.
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiError := ApiError{}
+		json.Unmarshal(resp.Body, &apiError)
 
-	// Attempt to parse response as a balanced api error
-	apiError := ApiError{}
-	if err := json.Unmarshal(respBytes, &apiError); err == nil {
-		// Check if api error is valid
-		if len(apiError.Status) != 0 {
-			return fmt.Errorf("Balanced API: Responded with error %g", apiError)
+		if apiError.StatusCode == 0 {
+			apiError.StatusCode = resp.StatusCode
+		}
+		if len(apiError.Status) == 0 {
+			apiError.Status = http.StatusText(resp.StatusCode)
 		}
+
+		return &apiError
 	}
 
 	// Attempt to parse response into out
 	if out != nil {
-		if err := json.Unmarshal(respBytes, out); err != nil {
-			return fmt.Errorf("Balanced API: Unable to parse response message %g", err)
+		if err := json.Unmarshal(resp.Body, out); err != nil {
+			return fmt.Errorf("%w: %w", ErrDecode, err)
 		}
 	}
 
 	return nil
 }
 
+// Sends a request over http. This is the innermost Handler of the middleware
+// chain.
+func (c *Client) send(ctx context.Context, r *Request) (*Response, error) {
+	// Build Uri
+	var uri bytes.Buffer
+	uri.WriteString(c.ApiRoot)
+	uri.WriteString(r.Path)
+
+	// Build Body
+	var body io.Reader
+	if r.Payload != nil && len(r.Payload) != 0 {
+		if r.Method == "GET" {
+			// GET request encode payload in uri, paths such as next_uri may
+			// already carry a query string
+			if strings.Contains(r.Path, "?") {
+				uri.WriteString("&")
+			} else {
+				uri.WriteString("?")
+			}
+			uri.WriteString(r.Payload.Encode())
+		} else {
+			// Not a GET request, encode payload into body of request
+			body = strings.NewReader(r.Payload.Encode())
+		}
+	}
+
+	// Build Request
+	req, err := http.NewRequestWithContext(ctx, r.Method, uri.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("Balanced API: Error creating %v request: %w", r.Method, err)
+	}
+
+	for key, values := range r.Header {
+		req.Header[key] = values
+	}
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, &networkError{Method: r.Method, Err: err}
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &networkError{Method: r.Method, Err: err}
+	}
+
+	return &Response{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       respBytes,
+	}, nil
+}
+
+func basicAuth(key string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(key+":"))
+}
+
 func addToPayload(payload url.Values, key, value string) {
 	// Check if empty
 	if len(value) != 0 {
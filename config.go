@@ -0,0 +1,121 @@
+package balanced
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Environment variables read by ConfigFromEnv.
+const (
+	EnvApiRoot       = "BALANCED_API_ROOT"
+	EnvApiKey        = "BALANCED_API_KEY"
+	EnvMarketplaceId = "BALANCED_MARKETPLACE_ID"
+)
+
+// Basic information needed to connect to the balanced REST API. The json keys
+// match the ones used in balanced.conf files.
+type Config struct {
+	ApiRoot       string `json:"balanced_api_root,omitempty"`
+	ApiKey        string `json:"balanced_api_key,omitempty"`
+	MarketplaceId string `json:"balanced_marketplace_id,omitempty"`
+}
+
+// Reads the configuration from the BALANCED_API_ROOT, BALANCED_API_KEY and
+// BALANCED_MARKETPLACE_ID environment variables. BALANCED_API_ROOT is optional
+// and defaults to DefaultApiRoot.
+func ConfigFromEnv() (*Config, error) {
+	config := &Config{
+		ApiRoot:       os.Getenv(EnvApiRoot),
+		ApiKey:        os.Getenv(EnvApiKey),
+		MarketplaceId: os.Getenv(EnvMarketplaceId),
+	}
+
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// Reads the configuration from a json file, i.e. balanced.conf:
+//
+//	{
+//		"balanced_api_root": "https://api.balancedpayments.com",
+//		"balanced_api_key": "...",
+//		"balanced_marketplace_id": "..."
+//	}
+func LoadConfigFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Balanced API: Unable to read config file: %w", err)
+	}
+
+	config := &Config{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("Balanced API: Unable to parse config file %v: %w", path, err)
+	}
+
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// Fills in defaults and checks that the required values are present.
+func (config *Config) validate() error {
+	if len(config.ApiRoot) == 0 {
+		config.ApiRoot = DefaultApiRoot
+	}
+
+	if len(config.ApiKey) == 0 {
+		return fmt.Errorf("Balanced API: Config is missing an api key")
+	}
+
+	if len(config.MarketplaceId) == 0 {
+		return fmt.Errorf("Balanced API: Config is missing a marketplace id")
+	}
+
+	return nil
+}
+
+// Creates a new client from the given configuration.
+func NewClientFromConfig(config *Config) (*Client, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	return NewClient(config.ApiRoot, config.ApiKey, config.MarketplaceId), nil
+}
+
+// Configures DefaultClient from the given configuration.
+func Configure(config *Config) error {
+	if err := config.validate(); err != nil {
+		return err
+	}
+
+	SetupEnvironment(config.ApiRoot, config.ApiKey, config.MarketplaceId)
+
+	return nil
+}
+
+// Configures DefaultClient from environment variables. See ConfigFromEnv.
+func ConfigureFromEnv() error {
+	config, err := ConfigFromEnv()
+	if err != nil {
+		return err
+	}
+
+	return Configure(config)
+}
+
+// Configures DefaultClient from a json config file. See LoadConfigFile.
+func ConfigureFromFile(path string) error {
+	config, err := LoadConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	return Configure(config)
+}
@@ -0,0 +1,90 @@
+package balanced
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nimajalali/balanced-go/balancedtest"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv(EnvApiRoot, "")
+	t.Setenv(EnvApiKey, "ak-test-key")
+	t.Setenv(EnvMarketplaceId, "TEST-MP123")
+
+	config, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatalf("Failed to read config from env: %v", err)
+	}
+
+	if config.ApiRoot != DefaultApiRoot || config.ApiKey != "ak-test-key" ||
+		config.MarketplaceId != "TEST-MP123" {
+		t.Fatalf("Invalid config read from env: %v", config)
+	}
+
+	t.Setenv(EnvApiKey, "")
+	if _, err := ConfigFromEnv(); err == nil {
+		t.Fatal("Expected an error for a config without an api key")
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "balanced.conf")
+	data := `{
+		"balanced_api_root": "https://example.com",
+		"balanced_api_key": "ak-test-key",
+		"balanced_marketplace_id": "TEST-MP123"
+	}`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load config file: %v", err)
+	}
+
+	client, err := NewClientFromConfig(config)
+	if err != nil {
+		t.Fatalf("Failed to create client from config: %v", err)
+	}
+
+	if client.ApiRoot != "https://example.com" || client.ApiKey != "ak-test-key" ||
+		client.MarketplaceId != "TEST-MP123" {
+		t.Fatalf("Invalid client created from config: %v", client)
+	}
+
+	if _, err := LoadConfigFile(path + ".missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Expected an error for a missing config file, got %v", err)
+	}
+}
+
+func TestSetupTestEnvironment(t *testing.T) {
+	server := balancedtest.NewServer()
+	defer server.Close()
+
+	defer SetupEnvironment(DefaultClient.ApiRoot, DefaultClient.ApiKey,
+		DefaultClient.MarketplaceId)
+
+	if err := setupTestEnvironment(server.URL); err != nil {
+		t.Fatalf("Failed to setup test environment: %v", err)
+	}
+
+	if DefaultClient.ApiRoot != server.URL || len(DefaultClient.ApiKey) == 0 ||
+		len(DefaultClient.MarketplaceId) == 0 {
+		t.Fatalf("Invalid test environment: %v", DefaultClient)
+	}
+
+	if _, err := CreateAccount(); err != nil {
+		t.Fatalf("Unable to use test environment: %v", err)
+	}
+
+	// The cause of a failure is kept
+	server.Close()
+	if err := setupTestEnvironment(server.URL); !errors.Is(err, ErrNetwork) {
+		t.Fatalf("Expected a network error, got %v", err)
+	}
+}
@@ -1,6 +1,8 @@
 package balanced
 
 import (
+	"context"
+	"iter"
 	"net/url"
 	"strconv"
 	"time"
@@ -11,40 +13,48 @@ const (
 )
 
 type Credit struct {
-	Account           Account     `json:"account,omitempty"`
-	Amount            int         `json:"amount,omitempty"`
-	AvailableAt       time.Time   `json:"available_at,omitempty"`
-	BankAccount       BankAccount `json:"bank_account,omitempty"`
-	CreatedAt         time.Time   `json:"created_at,omitempty"`
-	Description       string      `json:"description,omitempty"`
-	Destination       BankAccount `json:"destination,omitempty"`
-	Fee               string      `json:"fee,omitempty"`
-	Id                string      `json:"id,omitempty"`
-	IsVoid            bool        `json:"is_void,omitempty"`
-	Meta              MetaType    `json:"meta,omitempty"`
-	Status            string      `json:"status,omitempty"`
-	Source            Card        `json:"source,omitempty"`
-	TransactionNumber string      `json:"transaction_number,omitempty"`
-	Uri               string      `json:"uri,omitempty"`
-}
-
-type ListOfCredits struct {
-	FirstUri    string   `json:"first_uri,omitempty"`
-	Items       []Credit `json:"items,omitempty"`
-	LastUri     string   `json:"last_uri,omitempty"`
-	Limit       int      `json:"limit,omitempty"`
-	NextUri     string   `json:"next_uri,omitempty"`
-	Offset      int      `json:"offset,omitempty"`
-	PreviousUri string   `json:"previous_uri,omitempty"`
-	Total       int      `json:"total,omitempty"`
-	Uri         string   `json:"uri,omitempty"`
+	Account           Account           `json:"account,omitempty"`
+	Amount            int               `json:"amount,omitempty"`
+	AvailableAt       time.Time         `json:"available_at,omitempty"`
+	BankAccount       BankAccount       `json:"bank_account,omitempty"`
+	CreatedAt         time.Time         `json:"created_at,omitempty"`
+	Description       string            `json:"description,omitempty"`
+	Destination       FundingInstrument `json:"destination,omitempty"`
+	Fee               string            `json:"fee,omitempty"`
+	Id                string            `json:"id,omitempty"`
+	IsVoid            bool              `json:"is_void,omitempty"`
+	Meta              MetaType          `json:"meta,omitempty"`
+	Status            CreditStatus      `json:"status,omitempty"`
+	Source            FundingInstrument `json:"source,omitempty"`
+	TransactionNumber string            `json:"transaction_number,omitempty"`
+	Uri               string            `json:"uri,omitempty"`
 }
 
+type ListOfCredits = Page[Credit]
+
 // To credit a new bank account, you simply pass the amount along with the bank
 // account details. We do not store this bank account when you create a credit
 // this way, so you can safely assume that the information has been deleted.
+// The bank account details are checked with ValidateBankAccount before anything
+// is sent.
 // WARNING PCI Compliance required to use this functionality.
-func CreditNewBankAccount(amount int, description string, bankAccount *BankAccount) (credit *Credit, err error) {
+func (c *Client) CreditNewBankAccount(amount int, description string, bankAccount *BankAccount) (credit *Credit, err error) {
+	return c.CreditNewBankAccountContext(context.Background(), amount,
+		description, bankAccount)
+}
+
+// CreditNewBankAccountContext is like CreditNewBankAccount but carries the given context.
+func (c *Client) CreditNewBankAccountContext(ctx context.Context, amount int, description string, bankAccount *BankAccount) (credit *Credit, err error) {
+	if bankAccount == nil {
+		bankAccount = &BankAccount{}
+	}
+
+	err = ValidateBankAccount(bankAccount.Name, bankAccount.AccountNumber,
+		bankAccount.RoutingNumber, bankAccount.Type)
+	if err != nil {
+		return nil, err
+	}
+
 	// Required values
 	payload := url.Values{
 		"amount":                       {strconv.Itoa(amount)},
@@ -61,16 +71,33 @@ func CreditNewBankAccount(amount int, description string, bankAccount *BankAccou
 	}
 
 	credit = &Credit{}
-	err = post(creditsUri, payload, credit)
+	err = c.post(ctx, creditsUri, payload, credit)
 
 	return
 }
 
+// CreditNewBankAccount is a wrapper around DefaultClient.CreditNewBankAccount.
+func CreditNewBankAccount(amount int, description string, bankAccount *BankAccount) (credit *Credit, err error) {
+	return DefaultClient.CreditNewBankAccount(amount, description, bankAccount)
+}
+
+// CreditNewBankAccountContext is a wrapper around DefaultClient.CreditNewBankAccountContext.
+func CreditNewBankAccountContext(ctx context.Context, amount int, description string, bankAccount *BankAccount) (credit *Credit, err error) {
+	return DefaultClient.CreditNewBankAccountContext(ctx, amount, description,
+		bankAccount)
+}
+
 // To credit an existing bank account, you simply pass the amount to the nested
 // credit endpoint of a bank account. The credits_uri is a convenient uri
 // provided so that you can simply issue a POST with the amount and a credit
 // shall be created.
-func CreditExistingBankAccount(uri, description string, amount int) (credit *Credit, err error) {
+func (c *Client) CreditExistingBankAccount(uri, description string, amount int) (credit *Credit, err error) {
+	return c.CreditExistingBankAccountContext(context.Background(), uri,
+		description, amount)
+}
+
+// CreditExistingBankAccountContext is like CreditExistingBankAccount but carries the given context.
+func (c *Client) CreditExistingBankAccountContext(ctx context.Context, uri, description string, amount int) (credit *Credit, err error) {
 	// Required values
 	payload := url.Values{
 		"amount": {strconv.Itoa(amount)},
@@ -79,46 +106,136 @@ func CreditExistingBankAccount(uri, description string, amount int) (credit *Cre
 	addToPayload(payload, "description", description)
 
 	credit = &Credit{}
-	err = post(uri, payload, credit)
+	err = c.post(ctx, uri, payload, credit)
 
 	return
 }
 
+// CreditExistingBankAccount is a wrapper around DefaultClient.CreditExistingBankAccount.
+func CreditExistingBankAccount(uri, description string, amount int) (credit *Credit, err error) {
+	return DefaultClient.CreditExistingBankAccount(uri, description, amount)
+}
+
+// CreditExistingBankAccountContext is a wrapper around DefaultClient.CreditExistingBankAccountContext.
+func CreditExistingBankAccountContext(ctx context.Context, uri, description string, amount int) (credit *Credit, err error) {
+	return DefaultClient.CreditExistingBankAccountContext(ctx, uri, description,
+		amount)
+}
+
 // Retrieves the details of a credit that you've previously created. Use the uri
 // that was previously returned, and the corresponding credit information will
 // be returned.
-func RetrieveCredit(uri string) (credit *Credit, err error) {
+func (c *Client) RetrieveCredit(uri string) (credit *Credit, err error) {
+	return c.RetrieveCreditContext(context.Background(), uri)
+}
+
+// RetrieveCreditContext is like RetrieveCredit but carries the given context.
+func (c *Client) RetrieveCreditContext(ctx context.Context, uri string) (credit *Credit, err error) {
 	credit = &Credit{}
-	err = get(uri, nil, credit)
+	err = c.get(ctx, uri, nil, credit)
 
 	return
 }
 
+// RetrieveCredit is a wrapper around DefaultClient.RetrieveCredit.
+func RetrieveCredit(uri string) (credit *Credit, err error) {
+	return DefaultClient.RetrieveCredit(uri)
+}
+
+// RetrieveCreditContext is a wrapper around DefaultClient.RetrieveCreditContext.
+func RetrieveCreditContext(ctx context.Context, uri string) (credit *Credit, err error) {
+	return DefaultClient.RetrieveCreditContext(ctx, uri)
+}
+
 // Returns a list of credits you've previously created. The credits are returned
 // in sorted order, with the most recent credits appearing first.
-func ListAllCredits(limit, offset int) (listOfCredits *ListOfCredits, err error) {
+func (c *Client) ListAllCredits(limit, offset int) (listOfCredits *ListOfCredits, err error) {
+	return c.ListAllCreditsContext(context.Background(), limit, offset)
+}
+
+// ListAllCreditsContext is like ListAllCredits but carries the given context.
+func (c *Client) ListAllCreditsContext(ctx context.Context, limit, offset int) (listOfCredits *ListOfCredits, err error) {
 	payload := defaultPayload(limit, offset)
 
 	listOfCredits = &ListOfCredits{}
-	err = get(creditsUri, payload, listOfCredits)
+	err = c.get(ctx, creditsUri, payload, listOfCredits)
 
 	return
 }
 
+// ListAllCredits is a wrapper around DefaultClient.ListAllCredits.
+func ListAllCredits(limit, offset int) (listOfCredits *ListOfCredits, err error) {
+	return DefaultClient.ListAllCredits(limit, offset)
+}
+
+// ListAllCreditsContext is a wrapper around DefaultClient.ListAllCreditsContext.
+func ListAllCreditsContext(ctx context.Context, limit, offset int) (listOfCredits *ListOfCredits, err error) {
+	return DefaultClient.ListAllCreditsContext(ctx, limit, offset)
+}
+
+// Returns the credits selected by query, i.e. credits created in a given
+// period or with a given meta value. See Query.
+func (c *Client) ListAllCreditsWithQuery(query *Query) (listOfCredits *ListOfCredits, err error) {
+	return c.ListAllCreditsWithQueryContext(context.Background(), query)
+}
+
+// ListAllCreditsWithQueryContext is like ListAllCreditsWithQuery but carries the given context.
+func (c *Client) ListAllCreditsWithQueryContext(ctx context.Context, query *Query) (listOfCredits *ListOfCredits, err error) {
+	return List[Credit](ctx, c, creditsUri, query)
+}
+
+// ListAllCreditsWithQuery is a wrapper around DefaultClient.ListAllCreditsWithQuery.
+func ListAllCreditsWithQuery(query *Query) (listOfCredits *ListOfCredits, err error) {
+	return DefaultClient.ListAllCreditsWithQuery(query)
+}
+
+// ListAllCreditsWithQueryContext is a wrapper around DefaultClient.ListAllCreditsWithQueryContext.
+func ListAllCreditsWithQueryContext(ctx context.Context, query *Query) (listOfCredits *ListOfCredits, err error) {
+	return DefaultClient.ListAllCreditsWithQueryContext(ctx, query)
+}
+
 // Returns a list of credits you've previously created to a specific bank
 // account. The credits_uri is a convenient uri provided so that you can simply
 // issue a GET to the credits_uri. The credits are returned in sorted order,
 // with the most recent credits appearing first.
-func ListAllCreditsForBankAccount(uri string, limit, offset int) (listOfCredits *ListOfCredits, err error) {
+func (c *Client) ListAllCreditsForBankAccount(uri string, limit, offset int) (listOfCredits *ListOfCredits, err error) {
+	return c.ListAllCreditsForBankAccountContext(context.Background(), uri,
+		limit, offset)
+}
+
+// ListAllCreditsForBankAccountContext is like ListAllCreditsForBankAccount but carries the given context.
+func (c *Client) ListAllCreditsForBankAccountContext(ctx context.Context, uri string, limit, offset int) (listOfCredits *ListOfCredits, err error) {
 	payload := defaultPayload(limit, offset)
 
 	listOfCredits = &ListOfCredits{}
-	err = get(uri, payload, listOfCredits)
+	err = c.get(ctx, uri, payload, listOfCredits)
 
 	return
 }
 
-func CreateNewCreditForAccount(uri, description, appearsOnStatementAs,
+// ListAllCreditsForBankAccount is a wrapper around DefaultClient.ListAllCreditsForBankAccount.
+func ListAllCreditsForBankAccount(uri string, limit, offset int) (listOfCredits *ListOfCredits, err error) {
+	return DefaultClient.ListAllCreditsForBankAccount(uri, limit, offset)
+}
+
+// ListAllCreditsForBankAccountContext is a wrapper around DefaultClient.ListAllCreditsForBankAccountContext.
+func ListAllCreditsForBankAccountContext(ctx context.Context, uri string, limit, offset int) (listOfCredits *ListOfCredits, err error) {
+	return DefaultClient.ListAllCreditsForBankAccountContext(ctx, uri, limit,
+		offset)
+}
+
+func (c *Client) CreateNewCreditForAccount(uri, description, appearsOnStatementAs,
+	destinationUri, bankAccountUri string, amount int,
+	meta MetaType) (credit *Credit, err error) {
+
+	return c.CreateNewCreditForAccountContext(context.Background(), uri,
+		description, appearsOnStatementAs, destinationUri, bankAccountUri,
+		amount, meta)
+}
+
+// CreateNewCreditForAccountContext is like CreateNewCreditForAccount but carries the given context.
+func (c *Client) CreateNewCreditForAccountContext(ctx context.Context,
+	uri, description, appearsOnStatementAs,
 	destinationUri, bankAccountUri string, amount int,
 	meta MetaType) (credit *Credit, err error) {
 
@@ -133,18 +250,169 @@ func CreateNewCreditForAccount(uri, description, appearsOnStatementAs,
 	addToPayload(payload, "bank_account_uri", bankAccountsUri)
 
 	credit = &Credit{}
-	err = post(uri, payload, credit)
+	err = c.post(ctx, uri, payload, credit)
 
 	return
 }
 
-func ListAllCreditsForAccount(uri string, limit,
+// CreateNewCreditForAccount is a wrapper around DefaultClient.CreateNewCreditForAccount.
+func CreateNewCreditForAccount(uri, description, appearsOnStatementAs,
+	destinationUri, bankAccountUri string, amount int,
+	meta MetaType) (credit *Credit, err error) {
+
+	return DefaultClient.CreateNewCreditForAccount(uri, description,
+		appearsOnStatementAs, destinationUri, bankAccountUri, amount, meta)
+}
+
+// CreateNewCreditForAccountContext is a wrapper around DefaultClient.CreateNewCreditForAccountContext.
+func CreateNewCreditForAccountContext(ctx context.Context,
+	uri, description, appearsOnStatementAs,
+	destinationUri, bankAccountUri string, amount int,
+	meta MetaType) (credit *Credit, err error) {
+
+	return DefaultClient.CreateNewCreditForAccountContext(ctx, uri, description,
+		appearsOnStatementAs, destinationUri, bankAccountUri, amount, meta)
+}
+
+// Parameters for creating a credit with CreateNewCreditWithParams.
+type CreditParams struct {
+	// Amount in cents. Required.
+	Amount int
+	// Sequence of characters shown on the recipient's statement, at most 22.
+	AppearsOnStatementAs string
+	Description          string
+	// Uri of the bank account to credit. Defaults to the account's most
+	// recently added bank account.
+	DestinationUri string
+	// Same as DestinationUri, kept for older marketplaces.
+	BankAccountUri string
+	Meta           MetaType
+}
+
+// Checks the parameters without contacting balanced.
+func (p *CreditParams) Validate() error {
+	v := &ValidationError{}
+
+	checkAmount(v, p.Amount, false)
+	checkAppearsOnStatementAs(v, p.AppearsOnStatementAs)
+	checkUri(v, "destination_uri", p.DestinationUri, "bank_accounts")
+	checkUri(v, "bank_account_uri", p.BankAccountUri, "bank_accounts")
+
+	return v.err()
+}
+
+func (p *CreditParams) payload() url.Values {
+	payload := url.Values{}
+
+	addAmountToPayload(payload, p.Amount)
+	addToPayload(payload, "description", p.Description)
+	addToPayload(payload, "appears_on_statement_as", p.AppearsOnStatementAs)
+	addToPayload(payload, "destination_uri", p.DestinationUri)
+	addToPayload(payload, "bank_account_uri", p.BankAccountUri)
+	addMetaToPayload(payload, p.Meta)
+
+	return payload
+}
+
+// Creates a credit from named parameters, which are validated before the
+// request is sent. The uri is the credits_uri of an account or of a bank
+// account.
+func (c *Client) CreateNewCreditWithParams(uri string, params *CreditParams) (credit *Credit, err error) {
+	return c.CreateNewCreditWithParamsContext(context.Background(), uri, params)
+}
+
+// CreateNewCreditWithParamsContext is like CreateNewCreditWithParams but carries the given context.
+func (c *Client) CreateNewCreditWithParamsContext(ctx context.Context, uri string, params *CreditParams) (credit *Credit, err error) {
+	if params == nil {
+		params = &CreditParams{}
+	}
+
+	if err = params.Validate(); err != nil {
+		return nil, err
+	}
+
+	credit = &Credit{}
+	err = c.post(ctx, uri, params.payload(), credit)
+
+	return
+}
+
+// CreateNewCreditWithParams is a wrapper around DefaultClient.CreateNewCreditWithParams.
+func CreateNewCreditWithParams(uri string, params *CreditParams) (credit *Credit, err error) {
+	return DefaultClient.CreateNewCreditWithParams(uri, params)
+}
+
+// CreateNewCreditWithParamsContext is a wrapper around DefaultClient.CreateNewCreditWithParamsContext.
+func CreateNewCreditWithParamsContext(ctx context.Context, uri string, params *CreditParams) (credit *Credit, err error) {
+	return DefaultClient.CreateNewCreditWithParamsContext(ctx, uri, params)
+}
+
+func (c *Client) ListAllCreditsForAccount(uri string, limit,
+	offset int) (listOfCredits *ListOfCredits, err error) {
+
+	return c.ListAllCreditsForAccountContext(context.Background(), uri, limit,
+		offset)
+}
+
+// ListAllCreditsForAccountContext is like ListAllCreditsForAccount but carries the given context.
+func (c *Client) ListAllCreditsForAccountContext(ctx context.Context,
+	uri string, limit,
 	offset int) (listOfCredits *ListOfCredits, err error) {
 
 	payload := defaultPayload(limit, offset)
 
 	listOfCredits = &ListOfCredits{}
-	err = get(uri, payload, listOfCredits)
+	err = c.get(ctx, uri, payload, listOfCredits)
 
 	return
 }
+
+// ListAllCreditsForAccount is a wrapper around DefaultClient.ListAllCreditsForAccount.
+func ListAllCreditsForAccount(uri string, limit,
+	offset int) (listOfCredits *ListOfCredits, err error) {
+
+	return DefaultClient.ListAllCreditsForAccount(uri, limit, offset)
+}
+
+// ListAllCreditsForAccountContext is a wrapper around DefaultClient.ListAllCreditsForAccountContext.
+func ListAllCreditsForAccountContext(ctx context.Context,
+	uri string, limit,
+	offset int) (listOfCredits *ListOfCredits, err error) {
+
+	return DefaultClient.ListAllCreditsForAccountContext(ctx, uri, limit,
+		offset)
+}
+
+// Iterates over every credit you've previously created, most recent first.
+func (c *Client) IterateCredits(ctx context.Context, options *IterOptions) iter.Seq2[Credit, error] {
+	uri := creditsUri
+
+	return Iterate[Credit](ctx, c, uri, options)
+}
+
+// IterateCredits is a wrapper around DefaultClient.IterateCredits.
+func IterateCredits(ctx context.Context, options *IterOptions) iter.Seq2[Credit, error] {
+	return DefaultClient.IterateCredits(ctx, options)
+}
+
+// Iterates over every credit to the bank account whose credits_uri is given,
+// most recent first.
+func (c *Client) IterateCreditsForBankAccount(ctx context.Context, uri string, options *IterOptions) iter.Seq2[Credit, error] {
+	return Iterate[Credit](ctx, c, uri, options)
+}
+
+// IterateCreditsForBankAccount is a wrapper around DefaultClient.IterateCreditsForBankAccount.
+func IterateCreditsForBankAccount(ctx context.Context, uri string, options *IterOptions) iter.Seq2[Credit, error] {
+	return DefaultClient.IterateCreditsForBankAccount(ctx, uri, options)
+}
+
+// Iterates over every credit to the account whose credits_uri is given, most
+// recent first.
+func (c *Client) IterateCreditsForAccount(ctx context.Context, uri string, options *IterOptions) iter.Seq2[Credit, error] {
+	return Iterate[Credit](ctx, c, uri, options)
+}
+
+// IterateCreditsForAccount is a wrapper around DefaultClient.IterateCreditsForAccount.
+func IterateCreditsForAccount(ctx context.Context, uri string, options *IterOptions) iter.Seq2[Credit, error] {
+	return DefaultClient.IterateCreditsForAccount(ctx, uri, options)
+}
@@ -1,7 +1,9 @@
 package balanced
 
 import (
+	"context"
 	"fmt"
+	"iter"
 	"net/url"
 	"strconv"
 	"time"
@@ -12,42 +14,43 @@ const (
 )
 
 type Debit struct {
-	Account              Account   `json:"account,omitempty"`
-	Amount               int       `json:"amount,omitempty"`
-	AppearsOnStatementAs string    `json:"appears_on_statement_as,omitempty"`
-	AvailableAt          time.Time `json:"available_at,omitempty"`
-	CreatedAt            time.Time `json:"created_at,omitempty"`
-	Description          string    `json:"description,omitempty"`
-	Fee                  string    `json:"fee,omitempty"`
-	Hold                 Hold      `json:"hold,omitempty"`
-	Id                   string    `json:"id,omitempty"`
-	Meta                 MetaType  `json:"meta,omitempty"`
-	OnBehalfOf           string    `json:"on_behalf_of,omitempty"`
-	RefundsUri           string    `json:"refunds_uri,omitempty"`
-	Source               Card      `json:"source,omitempty"`
-	Status               string    `json:"status,omitempty"`
-	TransactionNumber    string    `json:"transaction_number,omitempty"`
-	Uri                  string    `json:"uri,omitempty"`
-}
-
-type ListOfDebits struct {
-	FirstUri    string  `json:"first_uri,omitempty"`
-	Items       []Debit `json:"items,omitempty"`
-	LastUri     string  `json:"last_uri,omitempty"`
-	Limit       int     `json:"limit,omitempty"`
-	NextUri     string  `json:"next_uri,omitempty"`
-	Offset      int     `json:"offset,omitempty"`
-	PreviousUri string  `json:"previous_uri,omitempty"`
-	Total       int     `json:"total,omitempty"`
-	Uri         string  `json:"uri,omitempty"`
+	Account              Account           `json:"account,omitempty"`
+	Amount               int               `json:"amount,omitempty"`
+	AppearsOnStatementAs string            `json:"appears_on_statement_as,omitempty"`
+	AvailableAt          time.Time         `json:"available_at,omitempty"`
+	CreatedAt            time.Time         `json:"created_at,omitempty"`
+	Description          string            `json:"description,omitempty"`
+	Fee                  string            `json:"fee,omitempty"`
+	Hold                 Hold              `json:"hold,omitempty"`
+	Id                   string            `json:"id,omitempty"`
+	Meta                 MetaType          `json:"meta,omitempty"`
+	OnBehalfOf           string            `json:"on_behalf_of,omitempty"`
+	RefundsUri           string            `json:"refunds_uri,omitempty"`
+	Source               FundingInstrument `json:"source,omitempty"`
+	Status               DebitStatus       `json:"status,omitempty"`
+	TransactionNumber    string            `json:"transaction_number,omitempty"`
+	Uri                  string            `json:"uri,omitempty"`
 }
 
+type ListOfDebits = Page[Debit]
+
 // Debits an account. Returns a uri that can later be used to reference this
 // debit. Successful creation of a debit using a card will return an associated
 // hold mapping as part of the response. This hold was created and captured
 // behind the scenes automatically. For ACH debits there is no corresponding
 // hold.
-func CreateNewDebit(uri, description, appearsOnStatementAs, accountUri,
+func (c *Client) CreateNewDebit(uri, description, appearsOnStatementAs, accountUri,
+	onBehalfOfUri, holdUri, sourceUri string, amount int,
+	meta MetaType) (debit *Debit, err error) {
+
+	return c.CreateNewDebitContext(context.Background(), uri, description,
+		appearsOnStatementAs, accountUri, onBehalfOfUri, holdUri, sourceUri,
+		amount, meta)
+}
+
+// CreateNewDebitContext is like CreateNewDebit but carries the given context.
+func (c *Client) CreateNewDebitContext(ctx context.Context,
+	uri, description, appearsOnStatementAs, accountUri,
 	onBehalfOfUri, holdUri, sourceUri string, amount int,
 	meta MetaType) (debit *Debit, err error) {
 
@@ -66,46 +69,222 @@ func CreateNewDebit(uri, description, appearsOnStatementAs, accountUri,
 	}
 
 	debit = &Debit{}
-	err = post(uri, payload, debit)
+	err = c.post(ctx, uri, payload, debit)
 
 	return
 }
 
+// CreateNewDebit is a wrapper around DefaultClient.CreateNewDebit.
+func CreateNewDebit(uri, description, appearsOnStatementAs, accountUri,
+	onBehalfOfUri, holdUri, sourceUri string, amount int,
+	meta MetaType) (debit *Debit, err error) {
+
+	return DefaultClient.CreateNewDebit(uri, description, appearsOnStatementAs,
+		accountUri, onBehalfOfUri, holdUri, sourceUri, amount, meta)
+}
+
+// CreateNewDebitContext is a wrapper around DefaultClient.CreateNewDebitContext.
+func CreateNewDebitContext(ctx context.Context,
+	uri, description, appearsOnStatementAs, accountUri,
+	onBehalfOfUri, holdUri, sourceUri string, amount int,
+	meta MetaType) (debit *Debit, err error) {
+
+	return DefaultClient.CreateNewDebitContext(ctx, uri, description,
+		appearsOnStatementAs, accountUri, onBehalfOfUri, holdUri, sourceUri,
+		amount, meta)
+}
+
+// Parameters for debiting an account with CreateNewDebitWithParams.
+type DebitParams struct {
+	// Amount in cents. Required, unless HoldUri is given, in which case it
+	// defaults to the amount of the hold.
+	Amount int
+	// Sequence of characters shown on the customer's statement, at most 22.
+	AppearsOnStatementAs string
+	Description          string
+	// Uri of the account to debit, when not posting to its debits_uri.
+	AccountUri string
+	// Uri of the merchant account the debit is made on behalf of.
+	OnBehalfOfUri string
+	// Uri of a hold to capture.
+	HoldUri string
+	// Uri of the card or bank account to debit. Defaults to the account's most
+	// recently added card.
+	SourceUri string
+	Meta      MetaType
+}
+
+// Checks the parameters without contacting balanced.
+func (p *DebitParams) Validate() error {
+	v := &ValidationError{}
+
+	checkAmount(v, p.Amount, len(p.HoldUri) != 0)
+	checkAppearsOnStatementAs(v, p.AppearsOnStatementAs)
+	checkUri(v, "account_uri", p.AccountUri, "accounts")
+	checkUri(v, "on_behalf_of_uri", p.OnBehalfOfUri, "accounts")
+	checkUri(v, "hold_uri", p.HoldUri, "holds")
+	checkUri(v, "source_uri", p.SourceUri, "cards", "bank_accounts")
+
+	return v.err()
+}
+
+func (p *DebitParams) payload() url.Values {
+	payload := url.Values{}
+
+	addAmountToPayload(payload, p.Amount)
+	addToPayload(payload, "description", p.Description)
+	addToPayload(payload, "appears_on_statement_as", p.AppearsOnStatementAs)
+	addToPayload(payload, "account_uri", p.AccountUri)
+	addToPayload(payload, "on_behalf_of_uri", p.OnBehalfOfUri)
+	addToPayload(payload, "hold_uri", p.HoldUri)
+	addToPayload(payload, "source_uri", p.SourceUri)
+	addMetaToPayload(payload, p.Meta)
+
+	return payload
+}
+
+// Debits an account from named parameters, which are validated before the
+// request is sent. The uri is the account's debits_uri, or the marketplace's
+// debits uri along with AccountUri.
+func (c *Client) CreateNewDebitWithParams(uri string, params *DebitParams) (debit *Debit, err error) {
+	return c.CreateNewDebitWithParamsContext(context.Background(), uri, params)
+}
+
+// CreateNewDebitWithParamsContext is like CreateNewDebitWithParams but carries the given context.
+func (c *Client) CreateNewDebitWithParamsContext(ctx context.Context, uri string, params *DebitParams) (debit *Debit, err error) {
+	if params == nil {
+		params = &DebitParams{}
+	}
+
+	if err = params.Validate(); err != nil {
+		return nil, err
+	}
+
+	debit = &Debit{}
+	err = c.post(ctx, uri, params.payload(), debit)
+
+	return
+}
+
+// CreateNewDebitWithParams is a wrapper around DefaultClient.CreateNewDebitWithParams.
+func CreateNewDebitWithParams(uri string, params *DebitParams) (debit *Debit, err error) {
+	return DefaultClient.CreateNewDebitWithParams(uri, params)
+}
+
+// CreateNewDebitWithParamsContext is a wrapper around DefaultClient.CreateNewDebitWithParamsContext.
+func CreateNewDebitWithParamsContext(ctx context.Context, uri string, params *DebitParams) (debit *Debit, err error) {
+	return DefaultClient.CreateNewDebitWithParamsContext(ctx, uri, params)
+}
+
 // Retrieves the details of a created debit.
-func RetrieveDebit(uri string) (debit *Debit, err error) {
+func (c *Client) RetrieveDebit(uri string) (debit *Debit, err error) {
+	return c.RetrieveDebitContext(context.Background(), uri)
+}
+
+// RetrieveDebitContext is like RetrieveDebit but carries the given context.
+func (c *Client) RetrieveDebitContext(ctx context.Context, uri string) (debit *Debit, err error) {
 	debit = &Debit{}
-	err = get(uri, nil, debit)
+	err = c.get(ctx, uri, nil, debit)
 
 	return
 }
 
+// RetrieveDebit is a wrapper around DefaultClient.RetrieveDebit.
+func RetrieveDebit(uri string) (debit *Debit, err error) {
+	return DefaultClient.RetrieveDebit(uri)
+}
+
+// RetrieveDebitContext is a wrapper around DefaultClient.RetrieveDebitContext.
+func RetrieveDebitContext(ctx context.Context, uri string) (debit *Debit, err error) {
+	return DefaultClient.RetrieveDebitContext(ctx, uri)
+}
+
 // Returns a list of debits you've previously created. The debits are returned
 // in sorted order, with the most recent debits appearing first.
-func ListAllDebits(limit, offset int) (listOfDebits *ListOfDebits, err error) {
+func (c *Client) ListAllDebits(limit, offset int) (listOfDebits *ListOfDebits, err error) {
+	return c.ListAllDebitsContext(context.Background(), limit, offset)
+}
+
+// ListAllDebitsContext is like ListAllDebits but carries the given context.
+func (c *Client) ListAllDebitsContext(ctx context.Context, limit, offset int) (listOfDebits *ListOfDebits, err error) {
 	payload := defaultPayload(limit, offset)
 
-	uri := fmt.Sprintf(debitsUri, marketplaceId)
+	uri := fmt.Sprintf(debitsUri, c.MarketplaceId)
 
 	listOfDebits = &ListOfDebits{}
-	err = get(uri, payload, listOfDebits)
+	err = c.get(ctx, uri, payload, listOfDebits)
 
 	return
 }
 
+// ListAllDebits is a wrapper around DefaultClient.ListAllDebits.
+func ListAllDebits(limit, offset int) (listOfDebits *ListOfDebits, err error) {
+	return DefaultClient.ListAllDebits(limit, offset)
+}
+
+// ListAllDebitsContext is a wrapper around DefaultClient.ListAllDebitsContext.
+func ListAllDebitsContext(ctx context.Context, limit, offset int) (listOfDebits *ListOfDebits, err error) {
+	return DefaultClient.ListAllDebitsContext(ctx, limit, offset)
+}
+
+// Returns the debits selected by query, i.e. debits created in a given
+// period or with a given meta value. See Query.
+func (c *Client) ListAllDebitsWithQuery(query *Query) (listOfDebits *ListOfDebits, err error) {
+	return c.ListAllDebitsWithQueryContext(context.Background(), query)
+}
+
+// ListAllDebitsWithQueryContext is like ListAllDebitsWithQuery but carries the given context.
+func (c *Client) ListAllDebitsWithQueryContext(ctx context.Context, query *Query) (listOfDebits *ListOfDebits, err error) {
+	uri := fmt.Sprintf(debitsUri, c.MarketplaceId)
+
+	return List[Debit](ctx, c, uri, query)
+}
+
+// ListAllDebitsWithQuery is a wrapper around DefaultClient.ListAllDebitsWithQuery.
+func ListAllDebitsWithQuery(query *Query) (listOfDebits *ListOfDebits, err error) {
+	return DefaultClient.ListAllDebitsWithQuery(query)
+}
+
+// ListAllDebitsWithQueryContext is a wrapper around DefaultClient.ListAllDebitsWithQueryContext.
+func ListAllDebitsWithQueryContext(ctx context.Context, query *Query) (listOfDebits *ListOfDebits, err error) {
+	return DefaultClient.ListAllDebitsWithQueryContext(ctx, query)
+}
+
 // Returns a list of debits you've previously created against a specific account
 // The debits_uri is a convenient uri provided so that you can simply issue a
 // GET to the debits_uri. The debits are returned in sorted order, with the most
 // recent debits appearing first.
-func ListAllDebitsForAccount(uri string, limit, offset int) (listOfDebits *ListOfDebits, err error) {
+func (c *Client) ListAllDebitsForAccount(uri string, limit, offset int) (listOfDebits *ListOfDebits, err error) {
+	return c.ListAllDebitsForAccountContext(context.Background(), uri, limit,
+		offset)
+}
+
+// ListAllDebitsForAccountContext is like ListAllDebitsForAccount but carries the given context.
+func (c *Client) ListAllDebitsForAccountContext(ctx context.Context, uri string, limit, offset int) (listOfDebits *ListOfDebits, err error) {
 	payload := defaultPayload(limit, offset)
 
 	listOfDebits = &ListOfDebits{}
-	err = get(uri, payload, listOfDebits)
+	err = c.get(ctx, uri, payload, listOfDebits)
 
 	return
 }
 
-func UpdateDebit(uri, description string, meta MetaType) (debit *Debit, err error) {
+// ListAllDebitsForAccount is a wrapper around DefaultClient.ListAllDebitsForAccount.
+func ListAllDebitsForAccount(uri string, limit, offset int) (listOfDebits *ListOfDebits, err error) {
+	return DefaultClient.ListAllDebitsForAccount(uri, limit, offset)
+}
+
+// ListAllDebitsForAccountContext is a wrapper around DefaultClient.ListAllDebitsForAccountContext.
+func ListAllDebitsForAccountContext(ctx context.Context, uri string, limit, offset int) (listOfDebits *ListOfDebits, err error) {
+	return DefaultClient.ListAllDebitsForAccountContext(ctx, uri, limit, offset)
+}
+
+func (c *Client) UpdateDebit(uri, description string, meta MetaType) (debit *Debit, err error) {
+	return c.UpdateDebitContext(context.Background(), uri, description, meta)
+}
+
+// UpdateDebitContext is like UpdateDebit but carries the given context.
+func (c *Client) UpdateDebitContext(ctx context.Context, uri, description string, meta MetaType) (debit *Debit, err error) {
 	payload := url.Values{}
 
 	addToPayload(payload, "description", description)
@@ -115,14 +294,62 @@ func UpdateDebit(uri, description string, meta MetaType) (debit *Debit, err erro
 	}
 
 	debit = &Debit{}
-	err = put(uri, payload, debit)
+	err = c.put(ctx, uri, payload, debit)
 
 	return
 }
 
-func RefundDebit(uri string) (refund *Refund, err error) {
+// UpdateDebit is a wrapper around DefaultClient.UpdateDebit.
+func UpdateDebit(uri, description string, meta MetaType) (debit *Debit, err error) {
+	return DefaultClient.UpdateDebit(uri, description, meta)
+}
+
+// UpdateDebitContext is a wrapper around DefaultClient.UpdateDebitContext.
+func UpdateDebitContext(ctx context.Context, uri, description string, meta MetaType) (debit *Debit, err error) {
+	return DefaultClient.UpdateDebitContext(ctx, uri, description, meta)
+}
+
+func (c *Client) RefundDebit(uri string) (refund *Refund, err error) {
+	return c.RefundDebitContext(context.Background(), uri)
+}
+
+// RefundDebitContext is like RefundDebit but carries the given context.
+func (c *Client) RefundDebitContext(ctx context.Context, uri string) (refund *Refund, err error) {
 	refund = &Refund{}
-	err = post(uri, nil, refund)
+	err = c.post(ctx, uri, nil, refund)
 
 	return
 }
+
+// RefundDebit is a wrapper around DefaultClient.RefundDebit.
+func RefundDebit(uri string) (refund *Refund, err error) {
+	return DefaultClient.RefundDebit(uri)
+}
+
+// RefundDebitContext is a wrapper around DefaultClient.RefundDebitContext.
+func RefundDebitContext(ctx context.Context, uri string) (refund *Refund, err error) {
+	return DefaultClient.RefundDebitContext(ctx, uri)
+}
+
+// Iterates over every debit you've previously created, most recent first.
+func (c *Client) IterateDebits(ctx context.Context, options *IterOptions) iter.Seq2[Debit, error] {
+	uri := fmt.Sprintf(debitsUri, c.MarketplaceId)
+
+	return Iterate[Debit](ctx, c, uri, options)
+}
+
+// IterateDebits is a wrapper around DefaultClient.IterateDebits.
+func IterateDebits(ctx context.Context, options *IterOptions) iter.Seq2[Debit, error] {
+	return DefaultClient.IterateDebits(ctx, options)
+}
+
+// Iterates over every debit created against the account whose debits_uri is
+// given, most recent first.
+func (c *Client) IterateDebitsForAccount(ctx context.Context, uri string, options *IterOptions) iter.Seq2[Debit, error] {
+	return Iterate[Debit](ctx, c, uri, options)
+}
+
+// IterateDebitsForAccount is a wrapper around DefaultClient.IterateDebitsForAccount.
+func IterateDebitsForAccount(ctx context.Context, uri string, options *IterOptions) iter.Seq2[Debit, error] {
+	return DefaultClient.IterateDebitsForAccount(ctx, uri, options)
+}
@@ -0,0 +1,112 @@
+package balanced
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDebit(t *testing.T) {
+	account := createAccountWithCard(t, testVisaCard)
+
+	debit := createNewDebit(t, account)
+	retrieveDebit(t, debit)
+	listAllDebitsForAccount(t, account, debit)
+	updateDebit(t, debit)
+
+	refund, err := RefundDebit(debit.RefundsUri)
+	if err != nil {
+		t.Fatalf("Failed to refund debit: %v", err)
+	}
+
+	if refund.Amount != debit.Amount || refund.Debit.Uri != debit.Uri {
+		t.Fatalf("Invalid refund created: %v", refund)
+	}
+}
+
+func TestDebitDeclined(t *testing.T) {
+	account := createAccountWithCard(t, testCancelledCard)
+
+	_, err := CreateNewDebit(account.DebitsUri, "", "", "", "", "", "", 500, nil)
+	if !errors.Is(err, ErrCardDeclined) {
+		t.Fatalf("Expected card to be declined, got %v", err)
+	}
+
+	account = createAccountWithCard(t, testInsufficientFunds)
+
+	_, err = CreateNewDebit(account.DebitsUri, "", "", "", "", "", "", 500, nil)
+	if !errors.Is(err, ErrInsufficientFunds) {
+		t.Fatalf("Expected insufficient funds, got %v", err)
+	}
+}
+
+func createAccountWithCard(t *testing.T, cardNumber string) *Account {
+	account, err := CreateAccount()
+	if err != nil {
+		t.Fatalf("Unable to create account: %v", err)
+	}
+
+	card, err := TokenizeCard(testExpirationYear, 12, cardNumber, "", "", "", "", "", "",
+		"", "", nil)
+	if err != nil {
+		t.Fatalf("Unable to create card: %v", err)
+	}
+
+	account, err = AddCardToAccount(account.Uri, card.Uri)
+	if err != nil {
+		t.Fatalf("Unable to add card to account: %v", err)
+	}
+
+	return account
+}
+
+func createNewDebit(t *testing.T, account *Account) *Debit {
+	debit, err := CreateNewDebit(account.DebitsUri, "Order #1",
+		"TEST ORDER", "", "", "", "", 1500, MetaType{"order_id": "1"})
+	if err != nil {
+		t.Fatalf("Failed to create debit: %v", err)
+	}
+
+	if debit.Amount != 1500 || debit.Status != "succeeded" ||
+		debit.Meta["order_id"] != "1" || len(debit.Hold.Uri) == 0 {
+		t.Fatalf("Invalid debit created: %v", debit)
+	}
+
+	return debit
+}
+
+func retrieveDebit(t *testing.T, d *Debit) {
+	debit, err := RetrieveDebit(d.Uri)
+	if err != nil {
+		t.Fatalf("Failed to retrieve debit: %v", err)
+	}
+
+	if debit.Id != d.Id || debit.Source.Card() == nil ||
+		debit.Source.Card().LastFour != "1111" {
+		t.Fatalf("Invalid debit retrieved: %v", debit)
+	}
+}
+
+func listAllDebitsForAccount(t *testing.T, account *Account, d *Debit) {
+	list, err := ListAllDebitsForAccount(account.DebitsUri, 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to retrieve list of debits: %v", err)
+	}
+
+	if len(list.Items) != 1 || list.Items[0].Id != d.Id {
+		t.Fatalf("Invalid list of debits: %v", list)
+	}
+}
+
+func updateDebit(t *testing.T, d *Debit) {
+	debit, err := UpdateDebit(d.Uri, "Order #1, shipped", MetaType{
+		"shipped": "true",
+	})
+	if err != nil {
+		t.Fatalf("Failed to update debit: %v", err)
+	}
+
+	if debit.Description != "Order #1, shipped" || debit.Meta["shipped"] != "true" ||
+		debit.Meta["order_id"] != "1" {
+		t.Fatalf("Failed to update debit: %v", debit)
+	}
+}
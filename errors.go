@@ -0,0 +1,100 @@
+package balanced
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errors returned by the api can be classified with errors.Is, i.e.
+//
+//	if errors.Is(err, balanced.ErrCardDeclined) { ... }
+//
+// The underlying *ApiError, if any, is available through errors.As.
+var (
+	// The card was declined by the issuer.
+	ErrCardDeclined = errors.New("Balanced API: Card declined")
+	// The funding source does not have enough funds.
+	ErrInsufficientFunds = errors.New("Balanced API: Insufficient funds")
+	// The requested resource does not exist.
+	ErrNotFound = errors.New("Balanced API: Not found")
+	// The api key is missing, invalid or not allowed to perform the request.
+	ErrUnauthorized = errors.New("Balanced API: Authentication failed")
+	// The request was rejected because of invalid parameters.
+	ErrValidation = errors.New("Balanced API: Invalid request")
+	// The request could not be sent or the response could not be read.
+	ErrNetwork = errors.New("Balanced API: Network error")
+	// The response could not be decoded.
+	ErrDecode = errors.New("Balanced API: Unable to decode response")
+	// A resource's status changed in a way that is not possible, i.e. from
+	// succeeded back to pending.
+	ErrInvalidTransition = errors.New("Balanced API: Invalid status transition")
+	// A debit or credit was still pending when the wait for it to settle
+	// was over.
+	ErrNotSettled = errors.New("Balanced API: Not settled yet")
+)
+
+// Returned when a request could not be sent or its response not read.
+type networkError struct {
+	Method string
+	Err    error
+}
+
+func (e *networkError) Error() string {
+	return fmt.Sprintf("Balanced API: Error sending %v request: %v", e.Method, e.Err)
+}
+
+func (e *networkError) Unwrap() error {
+	return e.Err
+}
+
+func (e *networkError) Is(target error) bool {
+	return target == ErrNetwork
+}
+
+// A parameter that failed validation.
+type FieldError struct {
+	// Name of the field, as sent to balanced, i.e. "account_uri".
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// Returned by the *WithParams operations when their parameters are invalid,
+// before any request is sent. Matches ErrValidation with errors.Is.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, fieldError := range e.Errors {
+		messages[i] = fieldError.Error()
+	}
+
+	return "Balanced API: Invalid parameters: " + strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// Records that field is invalid.
+func (e *ValidationError) add(field, format string, args ...interface{}) {
+	e.Errors = append(e.Errors, FieldError{
+		Field:   field,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// Returns the error, or nil if no field was invalid.
+func (e *ValidationError) err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+
+	return e
+}
@@ -0,0 +1,75 @@
+package balanced
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("connection refused")
+}
+
+// Creates a client that answers every request with the given status and body.
+func newStaticClient(status int, body string) *Client {
+	client := NewClient("http://balanced.invalid", "ak-test-key", "TEST-MP123")
+	client.Use(func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			return &Response{StatusCode: status, Body: []byte(body)}, nil
+		}
+	})
+
+	return client
+}
+
+func TestApiErrorClassification(t *testing.T) {
+	client := newStaticClient(http.StatusPaymentRequired, `{
+		"status": "Payment Required",
+		"status_code": 402,
+		"category_code": "card-declined",
+		"category_type": "banking",
+		"request_id": "OHM1234"
+	}`)
+
+	_, err := client.RetrieveDebit("/v1/marketplaces/TEST-MP123/debits/WD1")
+	if !errors.Is(err, ErrCardDeclined) || errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected a card declined error, got %v", err)
+	}
+
+	var apiErr *ApiError
+	if !errors.As(err, &apiErr) || apiErr.RequestId != "OHM1234" ||
+		apiErr.StatusCode != http.StatusPaymentRequired {
+		t.Fatalf("Unable to inspect api error: %v", err)
+	}
+}
+
+func TestApiErrorStatusCodeFallback(t *testing.T) {
+	client := newStaticClient(http.StatusNotFound, `{"status": "Not Found"}`)
+
+	_, err := client.RetrieveDebit("/v1/marketplaces/TEST-MP123/debits/WD1")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Expected a not found error, got %v", err)
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	client := newStaticClient(http.StatusOK, `{"amount": "not a number"}`)
+
+	_, err := client.RetrieveDebit("/v1/marketplaces/TEST-MP123/debits/WD1")
+	if !errors.Is(err, ErrDecode) {
+		t.Fatalf("Expected a decode error, got %v", err)
+	}
+}
+
+func TestNetworkError(t *testing.T) {
+	client := NewClient("http://balanced.invalid", "ak-test-key", "TEST-MP123")
+	client.SetTransport(failingTransport{})
+
+	_, err := client.RetrieveDebit("/v1/marketplaces/TEST-MP123/debits/WD1")
+	if !errors.Is(err, ErrNetwork) || errors.Is(err, ErrDecode) {
+		t.Fatalf("Expected a network error, got %v", err)
+	}
+}
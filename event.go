@@ -1,6 +1,11 @@
 package balanced
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"strings"
 	"time"
 )
 
@@ -11,13 +16,145 @@ const (
 type Event struct {
 	CallbackStatuses CallbackStatuses `json:"callback_statuses,omitempty"`
 	CallbackUri      string           `json:"callback_uri,omitempty"`
-	Entity           BankAccount      `json:"entity,omitempty"`
+	Entity           EventEntity      `json:"entity,omitempty"`
 	Id               string           `json:"id,omitempty"`
 	OccurredAt       time.Time        `json:"occurred_at,omitempty"`
 	Type             string           `json:"type,omitempty"`
 	Uri              string           `json:"uri,omitempty"`
 }
 
+// Decodes the event, falling back to the resource type in Type, i.e. "debit"
+// for "debit.succeeded", when the entity does not say what it is.
+func (e *Event) UnmarshalJSON(data []byte) error {
+	type event Event
+	if err := json.Unmarshal(data, (*event)(e)); err != nil {
+		return err
+	}
+
+	if len(e.Entity.Type) == 0 && len(e.Entity.Raw) != 0 {
+		resourceType, _, _ := strings.Cut(e.Type, ".")
+		return e.Entity.decode(resourceType)
+	}
+
+	return nil
+}
+
+// The resource an event is about. Value holds the decoded resource, use the
+// typed accessors to get at it:
+//
+//	if debit := event.Entity.Debit(); debit != nil {
+//		...
+//	}
+//
+// Entities of types the package does not know about are kept in Raw only.
+type EventEntity struct {
+	// Resource type, i.e. "debit", "bank_account".
+	Type string
+	// The entity as sent by balanced.
+	Raw json.RawMessage
+	// A *Debit, *Credit, *Hold, *Refund, *Card, *BankAccount or *Account, or
+	// nil for unknown types.
+	Value interface{}
+}
+
+// Creates an empty resource for each entity type.
+var eventEntityTypes = map[string]func() interface{}{
+	"account":      func() interface{} { return &Account{} },
+	"bank_account": func() interface{} { return &BankAccount{} },
+	"card":         func() interface{} { return &Card{} },
+	"credit":       func() interface{} { return &Credit{} },
+	"debit":        func() interface{} { return &Debit{} },
+	"hold":         func() interface{} { return &Hold{} },
+	"refund":       func() interface{} { return &Refund{} },
+}
+
+func (e *EventEntity) UnmarshalJSON(data []byte) error {
+	*e = EventEntity{}
+	if string(data) == "null" {
+		return nil
+	}
+
+	e.Raw = append(json.RawMessage{}, data...)
+
+	var typed struct {
+		ResourceType string `json:"_type"`
+	}
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return err
+	}
+
+	return e.decode(typed.ResourceType)
+}
+
+func (e EventEntity) MarshalJSON() ([]byte, error) {
+	if len(e.Raw) != 0 {
+		return e.Raw, nil
+	}
+
+	return json.Marshal(e.Value)
+}
+
+// Decodes Raw as the given resource type.
+func (e *EventEntity) decode(resourceType string) error {
+	e.Type = resourceType
+
+	newValue, ok := eventEntityTypes[resourceType]
+	if !ok {
+		return nil
+	}
+
+	value := newValue()
+	if err := json.Unmarshal(e.Raw, value); err != nil {
+		return fmt.Errorf("Balanced API: Unable to decode %v entity: %w",
+			resourceType, err)
+	}
+	e.Value = value
+
+	return nil
+}
+
+// Returns the entity if it is a debit, nil otherwise.
+func (e *EventEntity) Debit() *Debit {
+	debit, _ := e.Value.(*Debit)
+	return debit
+}
+
+// Returns the entity if it is a credit, nil otherwise.
+func (e *EventEntity) Credit() *Credit {
+	credit, _ := e.Value.(*Credit)
+	return credit
+}
+
+// Returns the entity if it is a hold, nil otherwise.
+func (e *EventEntity) Hold() *Hold {
+	hold, _ := e.Value.(*Hold)
+	return hold
+}
+
+// Returns the entity if it is a refund, nil otherwise.
+func (e *EventEntity) Refund() *Refund {
+	refund, _ := e.Value.(*Refund)
+	return refund
+}
+
+// Returns the entity if it is a card, nil otherwise.
+func (e *EventEntity) Card() *Card {
+	card, _ := e.Value.(*Card)
+	return card
+}
+
+// Returns the entity if it is a bank account, nil otherwise.
+func (e *EventEntity) BankAccount() *BankAccount {
+	bankAccount, _ := e.Value.(*BankAccount)
+	return bankAccount
+}
+
+// Returns the entity if it is an account, nil otherwise.
+func (e *EventEntity) Account() *Account {
+	account, _ := e.Value.(*Account)
+	return account
+}
+
 type CallbackStatuses struct {
 	Failed    int `json:"failed,omitempty"`
 	Pending   int `json:"pending,omitempty"`
@@ -25,35 +162,67 @@ type CallbackStatuses struct {
 	Succeeded int `json:"succeeded,omitempty"`
 }
 
-type ListOfEvents struct {
-	FirstUri    string  `json:"first_uri,omitempty"`
-	Items       []Event `json:"items,omitempty"`
-	LastUri     string  `json:"last_uri,omitempty"`
-	Limit       int     `json:"limit,omitempty"`
-	NextUri     string  `json:"next_uri,omitempty"`
-	Offset      int     `json:"offset,omitempty"`
-	PreviousUri string  `json:"previous_uri,omitempty"`
-	Total       int     `json:"total,omitempty"`
-	Uri         string  `json:"uri,omitempty"`
-}
+type ListOfEvents = Page[Event]
 
 // Retrieves the details of an event that was previously created. Use the uri
 // that was previously returned, and the corresponding event information will be
 // returned.
-func RetrieveEvent(uri string, limit, offset int) (event *Event, err error) {
+func (c *Client) RetrieveEvent(uri string, limit, offset int) (event *Event, err error) {
+	return c.RetrieveEventContext(context.Background(), uri, limit, offset)
+}
+
+// RetrieveEventContext is like RetrieveEvent but carries the given context.
+func (c *Client) RetrieveEventContext(ctx context.Context, uri string, limit, offset int) (event *Event, err error) {
 	payload := defaultPayload(limit, offset)
 
 	event = &Event{}
-	err = get(uri, payload, event)
+	err = c.get(ctx, uri, payload, event)
 
 	return
 }
 
-func ListAllEvents(limit, offset int) (listOfEvents *ListOfEvents, err error) {
+// RetrieveEvent is a wrapper around DefaultClient.RetrieveEvent.
+func RetrieveEvent(uri string, limit, offset int) (event *Event, err error) {
+	return DefaultClient.RetrieveEvent(uri, limit, offset)
+}
+
+// RetrieveEventContext is a wrapper around DefaultClient.RetrieveEventContext.
+func RetrieveEventContext(ctx context.Context, uri string, limit, offset int) (event *Event, err error) {
+	return DefaultClient.RetrieveEventContext(ctx, uri, limit, offset)
+}
+
+func (c *Client) ListAllEvents(limit, offset int) (listOfEvents *ListOfEvents, err error) {
+	return c.ListAllEventsContext(context.Background(), limit, offset)
+}
+
+// ListAllEventsContext is like ListAllEvents but carries the given context.
+func (c *Client) ListAllEventsContext(ctx context.Context, limit, offset int) (listOfEvents *ListOfEvents, err error) {
 	payload := defaultPayload(limit, offset)
 
 	listOfEvents = &ListOfEvents{}
-	err = get(eventsUri, payload, listOfEvents)
+	err = c.get(ctx, eventsUri, payload, listOfEvents)
 
 	return
 }
+
+// ListAllEvents is a wrapper around DefaultClient.ListAllEvents.
+func ListAllEvents(limit, offset int) (listOfEvents *ListOfEvents, err error) {
+	return DefaultClient.ListAllEvents(limit, offset)
+}
+
+// ListAllEventsContext is a wrapper around DefaultClient.ListAllEventsContext.
+func ListAllEventsContext(ctx context.Context, limit, offset int) (listOfEvents *ListOfEvents, err error) {
+	return DefaultClient.ListAllEventsContext(ctx, limit, offset)
+}
+
+// Iterates over every event.
+func (c *Client) IterateEvents(ctx context.Context, options *IterOptions) iter.Seq2[Event, error] {
+	uri := eventsUri
+
+	return Iterate[Event](ctx, c, uri, options)
+}
+
+// IterateEvents is a wrapper around DefaultClient.IterateEvents.
+func IterateEvents(ctx context.Context, options *IterOptions) iter.Seq2[Event, error] {
+	return DefaultClient.IterateEvents(ctx, options)
+}
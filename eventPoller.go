@@ -0,0 +1,261 @@
+package balanced
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+const (
+	defaultPollInterval = 30 * time.Second
+)
+
+// An EventPoller delivers the marketplace's events by polling ListAllEvents,
+// for receivers that cannot expose a url for callbacks. Events are handed to
+// Handler oldest first, and a checkpoint is saved after each one, so that a
+// restarted poller carries on with the first event it has not handled.
+//
+//	poller := balanced.NewEventPoller(client,
+//		balanced.NewFileCheckpointStore("balanced.checkpoint"), handler)
+//	err := poller.Run(ctx)
+//
+// When Handler returns an error the event is not checkpointed and Run stops,
+// so it is delivered again by the next run. Failures to list events that are
+// worth retrying, such as network errors and 5xx responses, do not stop Run:
+// it backs off and polls again.
+type EventPoller struct {
+	// Client to poll. When nil DefaultClient is used.
+	Client *Client
+	// Store for the checkpoint. When nil the checkpoint is only kept in
+	// memory.
+	Store   CheckpointStore
+	Handler EventHandler
+
+	// Time between polls. Defaults to 30 seconds.
+	Interval time.Duration
+	// Called by Run with the listing errors it backs off from, i.e. to log
+	// them.
+	OnError func(err error)
+	// Number of events requested per page. Defaults to 25.
+	PageSize int
+	// ReplayHistory makes a poller without a checkpoint deliver every past
+	// event. Otherwise it starts with the events that occur after its first
+	// poll.
+	ReplayHistory bool
+
+	checkpoint *Checkpoint
+	loaded     bool
+}
+
+// Creates a poller that hands the events of client to handler, keeping its
+// checkpoint in store.
+func NewEventPoller(client *Client, store CheckpointStore, handler EventHandler) *EventPoller {
+	return &EventPoller{
+		Client:  client,
+		Store:   store,
+		Handler: handler,
+	}
+}
+
+func (p *EventPoller) client() *Client {
+	if p.Client == nil {
+		return DefaultClient
+	}
+
+	return p.Client
+}
+
+// Returns the last handled event, loading it from Store on first use.
+func (p *EventPoller) Checkpoint(ctx context.Context) (*Checkpoint, error) {
+	if !p.loaded && p.Store != nil {
+		checkpoint, err := p.Store.LoadCheckpoint(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		p.checkpoint = checkpoint
+	}
+	p.loaded = true
+
+	return p.checkpoint, nil
+}
+
+func (p *EventPoller) saveCheckpoint(ctx context.Context, checkpoint *Checkpoint) error {
+	if p.Store != nil {
+		if err := p.Store.SaveCheckpoint(ctx, checkpoint); err != nil {
+			return err
+		}
+	}
+	p.checkpoint = checkpoint
+
+	return nil
+}
+
+// Returns the events after the checkpoint, oldest first.
+func (p *EventPoller) newEvents(ctx context.Context, checkpoint *Checkpoint) ([]Event, error) {
+	options := &IterOptions{PageSize: p.PageSize}
+
+	// Without a checkpoint only the most recent event is needed to start from
+	if checkpoint == nil && !p.ReplayHistory {
+		options.MaxItems = 1
+	}
+
+	events := []Event{}
+	seen := map[string]bool{}
+
+	// Events are listed most recent first
+	for event, err := range p.client().IterateEvents(ctx, options) {
+		if err != nil {
+			return nil, err
+		}
+
+		if checkpoint != nil && (event.Id == checkpoint.EventId ||
+			event.OccurredAt.Before(checkpoint.OccurredAt)) {
+			break
+		}
+
+		// Events created while paging shift older ones onto the next page
+		if seen[event.Id] {
+			continue
+		}
+		seen[event.Id] = true
+
+		events = append(events, event)
+	}
+
+	slices.Reverse(events)
+
+	return events, nil
+}
+
+// Hands every event that occurred since the last poll to Handler, oldest first,
+// and returns the number of events handled.
+func (p *EventPoller) Poll(ctx context.Context) (int, error) {
+	checkpoint, err := p.Checkpoint(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	polledAt := time.Now()
+
+	events, err := p.newEvents(ctx, checkpoint)
+	if err != nil {
+		return 0, err
+	}
+
+	return p.deliver(ctx, p.Handler, checkpoint, events, polledAt)
+}
+
+// Hands new events to handler, checkpointing each one, and returns the number
+// of events handled. polledAt is when the events were listed.
+func (p *EventPoller) deliver(ctx context.Context, handler EventHandler, checkpoint *Checkpoint, events []Event, polledAt time.Time) (int, error) {
+	// Start after the most recent event instead of replaying history
+	if checkpoint == nil && !p.ReplayHistory {
+		if len(events) == 0 {
+			// Start after the poll itself. Balanced may report times to
+			// the second, so events of the same second are delivered too.
+			return 0, p.saveCheckpoint(ctx, &Checkpoint{
+				OccurredAt: polledAt.Truncate(time.Second),
+			})
+		}
+
+		return 0, p.saveCheckpoint(ctx, eventCheckpoint(&events[len(events)-1]))
+	}
+
+	for i := range events {
+		if err := handler.HandleEvent(ctx, &events[i]); err != nil {
+			return i, err
+		}
+
+		if err := p.saveCheckpoint(ctx, eventCheckpoint(&events[i])); err != nil {
+			return i, err
+		}
+	}
+
+	return len(events), nil
+}
+
+// Polls every Interval until ctx is done, Handler fails or the checkpoint
+// cannot be loaded or saved. Listing errors that are worth retrying are handed
+// to OnError and polled again after a backoff that grows up to Interval.
+// Returns the error that stopped it, which is ctx.Err() once ctx is done.
+func (p *EventPoller) Run(ctx context.Context) error {
+	return p.run(ctx, p.Handler)
+}
+
+func (p *EventPoller) run(ctx context.Context, handler EventHandler) error {
+	interval := p.Interval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	backoff := &RetryPolicy{
+		InitialBackoff: min(time.Second, interval),
+		MaxBackoff:     interval,
+		Multiplier:     2,
+	}
+	failures := 0
+
+	for {
+		checkpoint, err := p.Checkpoint(ctx)
+		if err != nil {
+			return err
+		}
+
+		wait := interval
+		polledAt := time.Now()
+
+		events, err := p.newEvents(ctx, checkpoint)
+		switch {
+		case err == nil:
+			failures = 0
+			if _, err := p.deliver(ctx, handler, checkpoint, events, polledAt); err != nil {
+				return err
+			}
+
+		case ctx.Err() != nil:
+			return ctx.Err()
+
+		case isTransientError(err):
+			failures++
+			wait = backoff.backoff(failures)
+			if p.OnError != nil {
+				p.OnError(err)
+			}
+
+		default:
+			return err
+		}
+
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// Runs the poller in the background, sending events to the returned channel
+// instead of handing them to Handler, which is left untouched. An event is
+// checkpointed once it has been received from the channel. Both channels are
+// closed once the poller stops, after sending the error that stopped it.
+func (p *EventPoller) Stream(ctx context.Context) (<-chan Event, <-chan error) {
+	events := make(chan Event)
+	errs := make(chan error, 1)
+
+	handler := EventHandlerFunc(func(ctx context.Context, event *Event) error {
+		select {
+		case events <- *event:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+
+	go func() {
+		defer close(errs)
+		defer close(events)
+
+		errs <- p.run(ctx, handler)
+	}()
+
+	return events, errs
+}
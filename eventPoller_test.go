@@ -0,0 +1,285 @@
+package balanced
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nimajalali/balanced-go/balancedtest"
+)
+
+// Records the ids of the events it handles.
+type recordingHandler struct {
+	ids  []string
+	fail bool
+}
+
+func (h *recordingHandler) HandleEvent(ctx context.Context, event *Event) error {
+	if h.fail {
+		return errors.New("unable to handle event")
+	}
+
+	h.ids = append(h.ids, event.Id)
+	return nil
+}
+
+// Returns the ids of every event, oldest first.
+func allEventIds(t *testing.T, client *Client) []string {
+	ids := []string{}
+	for event, err := range client.IterateEvents(context.Background(), nil) {
+		if err != nil {
+			t.Fatalf("Failed to list events: %v", err)
+		}
+		ids = append(ids, event.Id)
+	}
+
+	slices.Reverse(ids)
+
+	return ids
+}
+
+func TestEventPoller(t *testing.T) {
+	server := balancedtest.NewServer()
+	defer server.Close()
+
+	client := NewClient(server.URL, server.ApiKey, server.MarketplaceId)
+	store := NewFileCheckpointStore(filepath.Join(t.TempDir(), "checkpoint.json"))
+	handler := &recordingHandler{}
+	ctx := context.Background()
+
+	poller := NewEventPoller(client, store, handler)
+	poller.PageSize = 2
+	poller.ReplayHistory = true
+
+	if _, err := poller.Poll(ctx); err != nil {
+		t.Fatalf("Failed to poll events: %v", err)
+	}
+
+	if !slices.Equal(handler.ids, allEventIds(t, client)) {
+		t.Fatalf("Expected every past event, got %v", handler.ids)
+	}
+
+	// Only new events are handled, oldest first
+	before := len(handler.ids)
+	if _, err := client.CreateAccount(); err != nil {
+		t.Fatalf("Unable to create account: %v", err)
+	}
+	if _, err := client.TokenizeCard(testExpirationYear, 12, testVisaCard, "", "", "", "", "",
+		"", "", "", nil); err != nil {
+		t.Fatalf("Unable to create card: %v", err)
+	}
+
+	count, err := poller.Poll(ctx)
+	if err != nil {
+		t.Fatalf("Failed to poll events: %v", err)
+	}
+
+	if count != 2 || !slices.Equal(handler.ids, allEventIds(t, client)) {
+		t.Fatalf("Expected 2 new events in order, got %v", handler.ids[before:])
+	}
+
+	// Failed events are delivered again by the next poll
+	if _, err := client.CreateAccount(); err != nil {
+		t.Fatalf("Unable to create account: %v", err)
+	}
+
+	handler.fail = true
+	if _, err := poller.Poll(ctx); err == nil {
+		t.Fatal("Expected handler failure to stop polling")
+	}
+
+	// A restarted poller resumes from the stored checkpoint
+	handler.fail = false
+	restarted := NewEventPoller(client, store, handler)
+
+	count, err = restarted.Poll(ctx)
+	if err != nil || count != 1 {
+		t.Fatalf("Expected the failed event to be redelivered, got %v, %v", count, err)
+	}
+
+	if !slices.Equal(handler.ids, allEventIds(t, client)) {
+		t.Fatalf("Invalid events after restart: %v", handler.ids)
+	}
+
+	checkpoint, err := store.LoadCheckpoint(ctx)
+	if err != nil || checkpoint.EventId != handler.ids[len(handler.ids)-1] {
+		t.Fatalf("Invalid checkpoint: %v, %v", checkpoint, err)
+	}
+}
+
+func TestEventPollerSkipsHistory(t *testing.T) {
+	server := balancedtest.NewServer()
+	defer server.Close()
+
+	client := NewClient(server.URL, server.ApiKey, server.MarketplaceId)
+	handler := &recordingHandler{}
+	poller := NewEventPoller(client, nil, handler)
+
+	count, err := poller.Poll(context.Background())
+	if err != nil || count != 0 {
+		t.Fatalf("Expected history to be skipped, got %v, %v", count, err)
+	}
+
+	if _, err := client.CreateAccount(); err != nil {
+		t.Fatalf("Unable to create account: %v", err)
+	}
+
+	count, err = poller.Poll(context.Background())
+	if err != nil || count != 1 {
+		t.Fatalf("Expected 1 new event, got %v, %v", count, err)
+	}
+}
+
+func TestEventPollerStream(t *testing.T) {
+	server := balancedtest.NewServer()
+	defer server.Close()
+
+	client := NewClient(server.URL, server.ApiKey, server.MarketplaceId)
+	poller := NewEventPoller(client, nil, nil)
+	poller.Interval = 10 * time.Millisecond
+	poller.ReplayHistory = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, errs := poller.Stream(ctx)
+
+	account, err := client.CreateAccount()
+	if err != nil {
+		t.Fatalf("Unable to create account: %v", err)
+	}
+
+	for found := false; !found; {
+		select {
+		case event := <-events:
+			entity := event.Entity.Account()
+			found = event.Type == "account.created" && entity != nil &&
+				entity.Uri == account.Uri
+		case <-time.After(time.Second):
+			t.Fatal("No event streamed")
+		}
+	}
+
+	cancel()
+
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the stream to be canceled, got %v", err)
+	}
+
+	if poller.Handler != nil {
+		t.Fatal("Expected the poller's handler to be left untouched")
+	}
+
+	if _, ok := <-events; ok {
+		t.Fatal("Expected the event channel to be closed")
+	}
+}
+
+func TestEventPollerRunRetries(t *testing.T) {
+	server := balancedtest.NewServer()
+	defer server.Close()
+
+	// Listing events fails twice before balanced recovers
+	failures := 2
+	client := NewClient(server.URL, server.ApiKey, server.MarketplaceId)
+	client.Use(func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			if failures > 0 {
+				failures--
+				return &Response{StatusCode: http.StatusServiceUnavailable,
+					Body: []byte(`{}`)}, nil
+			}
+
+			return next(ctx, req)
+		}
+	})
+
+	handled := make(chan string, 10)
+	handler := EventHandlerFunc(func(ctx context.Context, event *Event) error {
+		handled <- event.Id
+		return errors.New("unable to handle event")
+	})
+
+	poller := NewEventPoller(client, nil, handler)
+	poller.Interval = 10 * time.Millisecond
+	poller.ReplayHistory = true
+
+	var retried []error
+	poller.OnError = func(err error) { retried = append(retried, err) }
+
+	// Handler errors still stop the poller
+	err := poller.Run(context.Background())
+	if err == nil || err.Error() != "unable to handle event" {
+		t.Fatalf("Expected the handler error, got %v", err)
+	}
+
+	if len(retried) != 2 || !isTransientError(retried[0]) {
+		t.Fatalf("Expected 2 retried errors, got %v", retried)
+	}
+	if len(handled) != 1 {
+		t.Fatalf("Expected a single event to be handled, got %v", len(handled))
+	}
+
+	// Errors that are not worth retrying stop it too
+	unauthorized := NewClient(server.URL, "ak-invalid", server.MarketplaceId)
+	poller = NewEventPoller(unauthorized, nil, handler)
+	if err := poller.Run(context.Background()); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("Expected the poller to stop, got %v", err)
+	}
+}
+
+func TestEventPollerStartsWithoutEvents(t *testing.T) {
+	server := balancedtest.NewServer()
+	defer server.Close()
+
+	// The marketplace has no events yet when the poller first polls
+	empty := true
+	client := NewClient(server.URL, server.ApiKey, server.MarketplaceId)
+	client.Use(func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			if empty && strings.HasPrefix(req.Path, eventsUri) {
+				return &Response{StatusCode: http.StatusOK,
+					Body: []byte(`{"items": [], "total": 0}`)}, nil
+			}
+
+			return next(ctx, req)
+		}
+	})
+
+	store := NewFileCheckpointStore(filepath.Join(t.TempDir(), "checkpoint.json"))
+	handler := &recordingHandler{}
+	poller := NewEventPoller(client, store, handler)
+
+	count, err := poller.Poll(context.Background())
+	if err != nil || count != 0 {
+		t.Fatalf("Expected no events, got %v, %v", count, err)
+	}
+
+	checkpoint, err := store.LoadCheckpoint(context.Background())
+	if err != nil || checkpoint == nil || len(checkpoint.EventId) != 0 {
+		t.Fatalf("Expected a checkpoint at the first poll, got %v, %v", checkpoint, err)
+	}
+
+	empty = false
+	for i := 0; i < 3; i++ {
+		if _, err := client.CreateAccount(); err != nil {
+			t.Fatalf("Unable to create account: %v", err)
+		}
+	}
+
+	// Restarting resumes from the saved checkpoint
+	poller = NewEventPoller(client, store, handler)
+	if _, err := poller.Poll(context.Background()); err != nil {
+		t.Fatalf("Failed to poll events: %v", err)
+	}
+
+	ids := allEventIds(t, client)
+	created := ids[len(ids)-3:]
+	if len(handler.ids) < 3 ||
+		!slices.Equal(handler.ids[len(handler.ids)-3:], created) {
+		t.Fatalf("Expected events %v to be delivered, got %v", created, handler.ids)
+	}
+}
@@ -0,0 +1,100 @@
+package balanced
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEventEntity(t *testing.T) {
+	data := `[
+		{"id": "EV1", "type": "debit.succeeded", "entity": {"_type": "debit", "uri": "/v1/marketplaces/TEST-MP123/debits/WD1", "amount": 500}},
+		{"id": "EV2", "type": "hold.created", "entity": {"uri": "/v1/marketplaces/TEST-MP123/holds/HL1", "amount": 700}},
+		{"id": "EV3", "type": "credit.failed", "entity": {"_type": "credit", "uri": "/v1/credits/CR1", "status": "failed"}},
+		{"id": "EV4", "type": "bank_account.created", "entity": {"_type": "bank_account", "bank_name": "WELLS FARGO BANK N.A."}},
+		{"id": "EV5", "type": "dispute.created", "entity": {"_type": "dispute", "reason": "fraud"}},
+		{"id": "EV6", "type": "account.created"}
+	]`
+
+	var events []Event
+	if err := json.Unmarshal([]byte(data), &events); err != nil {
+		t.Fatalf("Failed to decode events: %v", err)
+	}
+
+	if debit := events[0].Entity.Debit(); debit == nil || debit.Amount != 500 ||
+		events[0].Entity.Hold() != nil {
+		t.Fatalf("Invalid debit entity: %+v", events[0].Entity)
+	}
+
+	// Entities without a _type take it from the event type
+	if hold := events[1].Entity.Hold(); hold == nil || hold.Amount != 700 ||
+		events[1].Entity.Type != "hold" {
+		t.Fatalf("Invalid hold entity: %+v", events[1].Entity)
+	}
+
+	if credit := events[2].Entity.Credit(); credit == nil || credit.Status != "failed" {
+		t.Fatalf("Invalid credit entity: %+v", events[2].Entity)
+	}
+
+	if bankAccount := events[3].Entity.BankAccount(); bankAccount == nil ||
+		bankAccount.BankName != "WELLS FARGO BANK N.A." {
+		t.Fatalf("Invalid bank account entity: %+v", events[3].Entity)
+	}
+
+	// Unknown entities are kept as json
+	unknown := events[4].Entity
+	if unknown.Type != "dispute" || unknown.Value != nil ||
+		string(unknown.Raw) != `{"_type": "dispute", "reason": "fraud"}` {
+		t.Fatalf("Invalid unknown entity: %+v", unknown)
+	}
+
+	if events[5].Entity.Value != nil || events[5].Entity.Account() != nil {
+		t.Fatalf("Invalid missing entity: %+v", events[5].Entity)
+	}
+
+	// Events survive a round trip
+	encoded, err := json.Marshal(events[1])
+	if err != nil {
+		t.Fatalf("Failed to encode event: %v", err)
+	}
+
+	event := Event{}
+	if err := json.Unmarshal(encoded, &event); err != nil ||
+		event.Entity.Hold() == nil || event.Entity.Hold().Amount != 700 {
+		t.Fatalf("Invalid event after round trip: %s", encoded)
+	}
+
+	err = json.Unmarshal([]byte(`{"type": "debit.created", "entity": {"amount": "500"}}`), &event)
+	if err == nil {
+		t.Fatal("Decoded a debit with an invalid amount")
+	}
+}
+
+func TestEventEntityFromApi(t *testing.T) {
+	account := createAccountWithCard(t, testVisaCard)
+
+	debit, err := CreateNewDebit(account.DebitsUri, "", "", "", "", "", "",
+		1200, nil)
+	if err != nil {
+		t.Fatalf("Failed to create debit: %v", err)
+	}
+
+	events, err := ListAllEvents(5, 0)
+	if err != nil {
+		t.Fatalf("Failed to list events: %v", err)
+	}
+
+	for _, event := range events.Items {
+		if event.Type != "debit.created" {
+			continue
+		}
+
+		if entity := event.Entity.Debit(); entity == nil || entity.Uri != debit.Uri ||
+			entity.Amount != 1200 {
+			t.Fatalf("Invalid debit entity: %+v", event.Entity)
+		}
+
+		return
+	}
+
+	t.Fatalf("No debit.created event found: %v", events.Items)
+}
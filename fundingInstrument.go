@@ -0,0 +1,115 @@
+package balanced
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Types of funding instruments, as found in FundingInstrument.Type.
+const (
+	FundingInstrumentCard        = "card"
+	FundingInstrumentBankAccount = "bank_account"
+)
+
+// The card or bank account funds are taken from or sent to, i.e. the Source of
+// a debit or the Destination of a credit. Value holds the decoded instrument,
+// use the typed accessors to get at it:
+//
+//	if card := debit.Source.Card(); card != nil {
+//		...
+//	}
+//
+// Instruments of types the package does not know about are kept in Raw only.
+type FundingInstrument struct {
+	// FundingInstrumentCard or FundingInstrumentBankAccount.
+	Type string
+	// The instrument as sent by balanced.
+	Raw json.RawMessage
+	// A *Card or *BankAccount, or nil for unknown types.
+	Value interface{}
+}
+
+func (f *FundingInstrument) UnmarshalJSON(data []byte) error {
+	*f = FundingInstrument{}
+	if string(data) == "null" {
+		return nil
+	}
+
+	f.Raw = append(json.RawMessage{}, data...)
+
+	var typed struct {
+		ResourceType string `json:"_type"`
+		Uri          string `json:"uri"`
+	}
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return err
+	}
+
+	// Older responses leave out the type, the uri tells them apart
+	f.Type = typed.ResourceType
+	if len(f.Type) == 0 {
+		switch uriKind(typed.Uri) {
+		case "cards":
+			f.Type = FundingInstrumentCard
+		case "bank_accounts":
+			f.Type = FundingInstrumentBankAccount
+		}
+	}
+
+	switch f.Type {
+	case FundingInstrumentCard:
+		f.Value = &Card{}
+	case FundingInstrumentBankAccount:
+		f.Value = &BankAccount{}
+	default:
+		return nil
+	}
+
+	if err := json.Unmarshal(data, f.Value); err != nil {
+		return fmt.Errorf("Balanced API: Unable to decode %v: %w", f.Type, err)
+	}
+
+	return nil
+}
+
+func (f FundingInstrument) MarshalJSON() ([]byte, error) {
+	if len(f.Raw) != 0 {
+		return f.Raw, nil
+	}
+
+	return json.Marshal(f.Value)
+}
+
+// Returns the instrument if it is a card, nil otherwise.
+func (f *FundingInstrument) Card() *Card {
+	card, _ := f.Value.(*Card)
+	return card
+}
+
+// Returns the instrument if it is a bank account, nil otherwise.
+func (f *FundingInstrument) BankAccount() *BankAccount {
+	bankAccount, _ := f.Value.(*BankAccount)
+	return bankAccount
+}
+
+// Returns the uri of the instrument, whatever its type.
+func (f *FundingInstrument) Uri() string {
+	switch value := f.Value.(type) {
+	case *Card:
+		return value.Uri
+	case *BankAccount:
+		return value.Uri
+	}
+
+	var untyped struct {
+		Uri string `json:"uri"`
+	}
+	json.Unmarshal(f.Raw, &untyped)
+
+	return untyped.Uri
+}
+
+// Reports whether balanced did not send an instrument.
+func (f *FundingInstrument) IsZero() bool {
+	return f.Value == nil && len(f.Raw) == 0
+}
@@ -0,0 +1,95 @@
+package balanced
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFundingInstrumentDecoding(t *testing.T) {
+	data := `{
+		"uri": "/v1/marketplaces/MP1/debits/WD1",
+		"amount": 5000,
+		"status": "pending",
+		"source": {
+			"_type": "bank_account",
+			"uri": "/v1/bank_accounts/BA1",
+			"name": "Johann Bernoulli",
+			"bank_name": "BANK OF AMERICA, N.A."
+		}
+	}`
+
+	debit := &Debit{}
+	if err := json.Unmarshal([]byte(data), debit); err != nil {
+		t.Fatalf("Failed to decode debit: %v", err)
+	}
+
+	bankAccount := debit.Source.BankAccount()
+	if bankAccount == nil || bankAccount.Name != "Johann Bernoulli" ||
+		debit.Source.Card() != nil || debit.Source.Uri() != "/v1/bank_accounts/BA1" {
+		t.Fatalf("Invalid bank account source decoded: %+v", debit.Source)
+	}
+
+	// Without a type the uri tells cards and bank accounts apart
+	data = `{
+		"uri": "/v1/credits/CR1",
+		"source": {"uri": "/v1/marketplaces/MP1/cards/CC1", "last_four": "1111"},
+		"destination": null
+	}`
+
+	credit := &Credit{}
+	if err := json.Unmarshal([]byte(data), credit); err != nil {
+		t.Fatalf("Failed to decode credit: %v", err)
+	}
+
+	card := credit.Source.Card()
+	if card == nil || card.LastFour != "1111" || credit.Source.Type != FundingInstrumentCard {
+		t.Fatalf("Invalid card source decoded: %+v", credit.Source)
+	}
+	if !credit.Destination.IsZero() || credit.Destination.Uri() != "" {
+		t.Fatalf("Expected no destination, got %+v", credit.Destination)
+	}
+
+	// Unknown instruments are kept as sent
+	data = `{"source": {"_type": "wallet", "uri": "/v1/wallets/WL1"}}`
+
+	hold := &Hold{}
+	if err := json.Unmarshal([]byte(data), hold); err != nil {
+		t.Fatalf("Failed to decode hold: %v", err)
+	}
+
+	if hold.Source.Type != "wallet" || hold.Source.Value != nil ||
+		hold.Source.Uri() != "/v1/wallets/WL1" {
+		t.Fatalf("Invalid unknown source decoded: %+v", hold.Source)
+	}
+
+	encoded, err := json.Marshal(hold.Source)
+	if err != nil || string(encoded) != `{"_type":"wallet","uri":"/v1/wallets/WL1"}` {
+		t.Fatalf("Expected the source to be encoded as received, got %s", encoded)
+	}
+}
+
+func TestFundingInstruments(t *testing.T) {
+	account := createAccountWithCard(t, testVisaCard)
+	debit := createNewDebit(t, account)
+
+	if debit.Source.Type != FundingInstrumentCard || debit.Source.Card() == nil {
+		t.Fatalf("Expected the debit to come from a card, got %+v", debit.Source)
+	}
+
+	refund, err := RefundDebit(debit.RefundsUri)
+	if err != nil {
+		t.Fatalf("Failed to refund debit: %v", err)
+	}
+
+	if refund.Destination().Uri() != debit.Source.Uri() {
+		t.Fatalf("Expected the refund to go back to %v, got %+v",
+			debit.Source.Uri(), refund.Destination())
+	}
+
+	credit := creditNewBankAccount(t)
+	bankAccount := credit.Destination.BankAccount()
+	if bankAccount == nil || bankAccount.Name != "Johann Bernoulli" {
+		t.Fatalf("Expected the credit to go to a bank account, got %+v",
+			credit.Destination)
+	}
+}
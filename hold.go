@@ -1,7 +1,9 @@
 package balanced
 
 import (
+	"context"
 	"fmt"
+	"iter"
 	"net/url"
 	"strconv"
 	"time"
@@ -12,35 +14,35 @@ const (
 )
 
 type Hold struct {
-	Account           Account   `json:"account,omitempty"`
-	Amount            int       `json:"amount,omitempty"`
-	CreatedAt         time.Time `json:"created_at,omitempty"`
-	Description       string    `json:"description,omitempty"`
-	ExpiresAt         time.Time `json:"expires_at,omitempty"`
-	Fee               string    `json:"fee,omitempty"`
-	Id                string    `json:"id,omitempty"`
-	IsVoid            bool      `json:"is_void,omitempty"`
-	Meta              MetaType  `json:"meta,omitempty"`
-	Source            Card      `json:"source,omitempty"`
-	TransactionNumber string    `json:"transaction_number,omitempty"`
-	Uri               string    `json:"uri,omitempty"`
-}
-
-type ListOfHolds struct {
-	FirstUri    string `json:"first_uri,omitempty"`
-	Items       []Hold `json:"items,omitempty"`
-	LastUri     string `json:"last_uri,omitempty"`
-	Limit       int    `json:"limit,omitempty"`
-	NextUri     string `json:"next_uri,omitempty"`
-	Offset      int    `json:"offset,omitempty"`
-	PreviousUri string `json:"previous_uri,omitempty"`
-	Total       int    `json:"total,omitempty"`
-	Uri         string `json:"uri,omitempty"`
+	Account           Account           `json:"account,omitempty"`
+	Amount            int               `json:"amount,omitempty"`
+	CreatedAt         time.Time         `json:"created_at,omitempty"`
+	Debit             *Debit            `json:"debit,omitempty"`
+	Description       string            `json:"description,omitempty"`
+	ExpiresAt         time.Time         `json:"expires_at,omitempty"`
+	Fee               string            `json:"fee,omitempty"`
+	Id                string            `json:"id,omitempty"`
+	IsVoid            bool              `json:"is_void,omitempty"`
+	Meta              MetaType          `json:"meta,omitempty"`
+	Source            FundingInstrument `json:"source,omitempty"`
+	TransactionNumber string            `json:"transaction_number,omitempty"`
+	Uri               string            `json:"uri,omitempty"`
 }
 
+type ListOfHolds = Page[Hold]
+
 // Creates a hold against a card. Returns a uri that can later be used to create
 // a debit, up to the full amount of the hold.
-func CreateNewHold(uri, accountUri, appearsOnStatementAs, description, sourceUri,
+func (c *Client) CreateNewHold(uri, accountUri, appearsOnStatementAs, description, sourceUri,
+	cardUri string, amount int, meta MetaType) (hold *Hold, err error) {
+
+	return c.CreateNewHoldContext(context.Background(), uri, accountUri,
+		appearsOnStatementAs, description, sourceUri, cardUri, amount, meta)
+}
+
+// CreateNewHoldContext is like CreateNewHold but carries the given context.
+func (c *Client) CreateNewHoldContext(ctx context.Context,
+	uri, accountUri, appearsOnStatementAs, description, sourceUri,
 	cardUri string, amount int, meta MetaType) (hold *Hold, err error) {
 
 	payload := url.Values{
@@ -58,47 +60,217 @@ func CreateNewHold(uri, accountUri, appearsOnStatementAs, description, sourceUri
 	}
 
 	hold = &Hold{}
-	err = post(uri, payload, hold)
+	err = c.post(ctx, uri, payload, hold)
+
+	return
+}
+
+// CreateNewHold is a wrapper around DefaultClient.CreateNewHold.
+func CreateNewHold(uri, accountUri, appearsOnStatementAs, description, sourceUri,
+	cardUri string, amount int, meta MetaType) (hold *Hold, err error) {
+
+	return DefaultClient.CreateNewHold(uri, accountUri, appearsOnStatementAs,
+		description, sourceUri, cardUri, amount, meta)
+}
+
+// CreateNewHoldContext is a wrapper around DefaultClient.CreateNewHoldContext.
+func CreateNewHoldContext(ctx context.Context,
+	uri, accountUri, appearsOnStatementAs, description, sourceUri,
+	cardUri string, amount int, meta MetaType) (hold *Hold, err error) {
+
+	return DefaultClient.CreateNewHoldContext(ctx, uri, accountUri,
+		appearsOnStatementAs, description, sourceUri, cardUri, amount, meta)
+}
+
+// Parameters for creating a hold with CreateNewHoldWithParams.
+type HoldParams struct {
+	// Amount in cents. Required.
+	Amount int
+	// Sequence of characters shown on the customer's statement, at most 22.
+	AppearsOnStatementAs string
+	Description          string
+	// Uri of the account to hold funds from, when not posting to its
+	// holds_uri.
+	AccountUri string
+	// Uri of the card to hold funds on. Defaults to the account's most
+	// recently added card.
+	SourceUri string
+	// Same as SourceUri, kept for older marketplaces.
+	CardUri string
+	Meta    MetaType
+}
+
+// Checks the parameters without contacting balanced.
+func (p *HoldParams) Validate() error {
+	v := &ValidationError{}
+
+	checkAmount(v, p.Amount, false)
+	checkAppearsOnStatementAs(v, p.AppearsOnStatementAs)
+	checkUri(v, "account_uri", p.AccountUri, "accounts")
+	checkUri(v, "source_uri", p.SourceUri, "cards")
+	checkUri(v, "card_uri", p.CardUri, "cards")
+
+	return v.err()
+}
+
+func (p *HoldParams) payload() url.Values {
+	payload := url.Values{}
+
+	addAmountToPayload(payload, p.Amount)
+	addToPayload(payload, "account_uri", p.AccountUri)
+	addToPayload(payload, "appears_on_statement_as", p.AppearsOnStatementAs)
+	addToPayload(payload, "description", p.Description)
+	addToPayload(payload, "source_uri", p.SourceUri)
+	addToPayload(payload, "card_uri", p.CardUri)
+	addMetaToPayload(payload, p.Meta)
+
+	return payload
+}
+
+// Creates a hold from named parameters, which are validated before the
+// request is sent. The uri is the account's holds_uri, or the marketplace's
+// holds uri along with AccountUri.
+func (c *Client) CreateNewHoldWithParams(uri string, params *HoldParams) (hold *Hold, err error) {
+	return c.CreateNewHoldWithParamsContext(context.Background(), uri, params)
+}
+
+// CreateNewHoldWithParamsContext is like CreateNewHoldWithParams but carries the given context.
+func (c *Client) CreateNewHoldWithParamsContext(ctx context.Context, uri string, params *HoldParams) (hold *Hold, err error) {
+	if params == nil {
+		params = &HoldParams{}
+	}
+
+	if err = params.Validate(); err != nil {
+		return nil, err
+	}
+
+	hold = &Hold{}
+	err = c.post(ctx, uri, params.payload(), hold)
 
 	return
 }
 
+// CreateNewHoldWithParams is a wrapper around DefaultClient.CreateNewHoldWithParams.
+func CreateNewHoldWithParams(uri string, params *HoldParams) (hold *Hold, err error) {
+	return DefaultClient.CreateNewHoldWithParams(uri, params)
+}
+
+// CreateNewHoldWithParamsContext is a wrapper around DefaultClient.CreateNewHoldWithParamsContext.
+func CreateNewHoldWithParamsContext(ctx context.Context, uri string, params *HoldParams) (hold *Hold, err error) {
+	return DefaultClient.CreateNewHoldWithParamsContext(ctx, uri, params)
+}
+
 // Retrieves the details of a hold that you've previously created. Use the uri
 // that was previously returned, and the corresponding hold information will be
 // returned.
-func RetrieveHold(uri string) (hold *Hold, err error) {
+func (c *Client) RetrieveHold(uri string) (hold *Hold, err error) {
+	return c.RetrieveHoldContext(context.Background(), uri)
+}
+
+// RetrieveHoldContext is like RetrieveHold but carries the given context.
+func (c *Client) RetrieveHoldContext(ctx context.Context, uri string) (hold *Hold, err error) {
 	hold = &Hold{}
-	err = get(uri, nil, hold)
+	err = c.get(ctx, uri, nil, hold)
 
 	return
 }
 
+// RetrieveHold is a wrapper around DefaultClient.RetrieveHold.
+func RetrieveHold(uri string) (hold *Hold, err error) {
+	return DefaultClient.RetrieveHold(uri)
+}
+
+// RetrieveHoldContext is a wrapper around DefaultClient.RetrieveHoldContext.
+func RetrieveHoldContext(ctx context.Context, uri string) (hold *Hold, err error) {
+	return DefaultClient.RetrieveHoldContext(ctx, uri)
+}
+
 // Returns a list of holds you've previously created. The holds are returned in
 // sorted order, with the most recent holds appearing first.
-func ListAllHolds(limit, offset int) (listOfHolds *ListOfHolds, err error) {
+func (c *Client) ListAllHolds(limit, offset int) (listOfHolds *ListOfHolds, err error) {
+	return c.ListAllHoldsContext(context.Background(), limit, offset)
+}
+
+// ListAllHoldsContext is like ListAllHolds but carries the given context.
+func (c *Client) ListAllHoldsContext(ctx context.Context, limit, offset int) (listOfHolds *ListOfHolds, err error) {
 	payload := defaultPayload(limit, offset)
 
-	uri := fmt.Sprintf(holdsUri, marketplaceId)
+	uri := fmt.Sprintf(holdsUri, c.MarketplaceId)
 
 	listOfHolds = &ListOfHolds{}
-	err = get(uri, payload, listOfHolds)
+	err = c.get(ctx, uri, payload, listOfHolds)
 
 	return
 }
 
+// ListAllHolds is a wrapper around DefaultClient.ListAllHolds.
+func ListAllHolds(limit, offset int) (listOfHolds *ListOfHolds, err error) {
+	return DefaultClient.ListAllHolds(limit, offset)
+}
+
+// ListAllHoldsContext is a wrapper around DefaultClient.ListAllHoldsContext.
+func ListAllHoldsContext(ctx context.Context, limit, offset int) (listOfHolds *ListOfHolds, err error) {
+	return DefaultClient.ListAllHoldsContext(ctx, limit, offset)
+}
+
+// Returns the holds selected by query, i.e. holds created in a given
+// period or with a given meta value. See Query.
+func (c *Client) ListAllHoldsWithQuery(query *Query) (listOfHolds *ListOfHolds, err error) {
+	return c.ListAllHoldsWithQueryContext(context.Background(), query)
+}
+
+// ListAllHoldsWithQueryContext is like ListAllHoldsWithQuery but carries the given context.
+func (c *Client) ListAllHoldsWithQueryContext(ctx context.Context, query *Query) (listOfHolds *ListOfHolds, err error) {
+	uri := fmt.Sprintf(holdsUri, c.MarketplaceId)
+
+	return List[Hold](ctx, c, uri, query)
+}
+
+// ListAllHoldsWithQuery is a wrapper around DefaultClient.ListAllHoldsWithQuery.
+func ListAllHoldsWithQuery(query *Query) (listOfHolds *ListOfHolds, err error) {
+	return DefaultClient.ListAllHoldsWithQuery(query)
+}
+
+// ListAllHoldsWithQueryContext is a wrapper around DefaultClient.ListAllHoldsWithQueryContext.
+func ListAllHoldsWithQueryContext(ctx context.Context, query *Query) (listOfHolds *ListOfHolds, err error) {
+	return DefaultClient.ListAllHoldsWithQueryContext(ctx, query)
+}
+
 // Returns a list of holds you've previously created. The holds are returned in
 // sorted order, with the most recent holds appearing first.
-func ListAllHoldsForAccount(uri string, limit, offset int) (listOfHolds *ListOfHolds, err error) {
+func (c *Client) ListAllHoldsForAccount(uri string, limit, offset int) (listOfHolds *ListOfHolds, err error) {
+	return c.ListAllHoldsForAccountContext(context.Background(), uri, limit,
+		offset)
+}
+
+// ListAllHoldsForAccountContext is like ListAllHoldsForAccount but carries the given context.
+func (c *Client) ListAllHoldsForAccountContext(ctx context.Context, uri string, limit, offset int) (listOfHolds *ListOfHolds, err error) {
 	payload := defaultPayload(limit, offset)
 
 	listOfHolds = &ListOfHolds{}
-	err = get(uri, payload, listOfHolds)
+	err = c.get(ctx, uri, payload, listOfHolds)
 
 	return
 }
 
+// ListAllHoldsForAccount is a wrapper around DefaultClient.ListAllHoldsForAccount.
+func ListAllHoldsForAccount(uri string, limit, offset int) (listOfHolds *ListOfHolds, err error) {
+	return DefaultClient.ListAllHoldsForAccount(uri, limit, offset)
+}
+
+// ListAllHoldsForAccountContext is a wrapper around DefaultClient.ListAllHoldsForAccountContext.
+func ListAllHoldsForAccountContext(ctx context.Context, uri string, limit, offset int) (listOfHolds *ListOfHolds, err error) {
+	return DefaultClient.ListAllHoldsForAccountContext(ctx, uri, limit, offset)
+}
+
 // Updates information about a hold
-func UpdateHold(uri, description, appearsOnStatementAs string, isVoid bool, meta MetaType) (hold *Hold, err error) {
+func (c *Client) UpdateHold(uri, description, appearsOnStatementAs string, isVoid bool, meta MetaType) (hold *Hold, err error) {
+	return c.UpdateHoldContext(context.Background(), uri, description,
+		appearsOnStatementAs, isVoid, meta)
+}
+
+// UpdateHoldContext is like UpdateHold but carries the given context.
+func (c *Client) UpdateHoldContext(ctx context.Context, uri, description, appearsOnStatementAs string, isVoid bool, meta MetaType) (hold *Hold, err error) {
 	payload := url.Values{}
 
 	addToPayload(payload, "description", description)
@@ -110,13 +282,31 @@ func UpdateHold(uri, description, appearsOnStatementAs string, isVoid bool, meta
 	}
 
 	hold = &Hold{}
-	err = put(uri, payload, hold)
+	err = c.put(ctx, uri, payload, hold)
 
 	return
 }
 
+// UpdateHold is a wrapper around DefaultClient.UpdateHold.
+func UpdateHold(uri, description, appearsOnStatementAs string, isVoid bool, meta MetaType) (hold *Hold, err error) {
+	return DefaultClient.UpdateHold(uri, description, appearsOnStatementAs,
+		isVoid, meta)
+}
+
+// UpdateHoldContext is a wrapper around DefaultClient.UpdateHoldContext.
+func UpdateHoldContext(ctx context.Context, uri, description, appearsOnStatementAs string, isVoid bool, meta MetaType) (hold *Hold, err error) {
+	return DefaultClient.UpdateHoldContext(ctx, uri, description,
+		appearsOnStatementAs, isVoid, meta)
+}
+
 // Captures a hold. This creates a debit.
-func CaptureHold(uri, holdUri, description, appearsOnStatementAs string) (debit *Debit, err error) {
+func (c *Client) CaptureHold(uri, holdUri, description, appearsOnStatementAs string) (debit *Debit, err error) {
+	return c.CaptureHoldContext(context.Background(), uri, holdUri, description,
+		appearsOnStatementAs)
+}
+
+// CaptureHoldContext is like CaptureHold but carries the given context.
+func (c *Client) CaptureHoldContext(ctx context.Context, uri, holdUri, description, appearsOnStatementAs string) (debit *Debit, err error) {
 	payload := url.Values{}
 
 	addToPayload(payload, "hold_uri", holdUri)
@@ -124,21 +314,103 @@ func CaptureHold(uri, holdUri, description, appearsOnStatementAs string) (debit
 	addToPayload(payload, "appears_on_statement_as", appearsOnStatementAs)
 
 	debit = &Debit{}
-	err = post(uri, payload, debit)
+	err = c.post(ctx, uri, payload, debit)
 
 	return
 }
 
+// CaptureHold is a wrapper around DefaultClient.CaptureHold.
+func CaptureHold(uri, holdUri, description, appearsOnStatementAs string) (debit *Debit, err error) {
+	return DefaultClient.CaptureHold(uri, holdUri, description,
+		appearsOnStatementAs)
+}
+
+// CaptureHoldContext is a wrapper around DefaultClient.CaptureHoldContext.
+func CaptureHoldContext(ctx context.Context, uri, holdUri, description, appearsOnStatementAs string) (debit *Debit, err error) {
+	return DefaultClient.CaptureHoldContext(ctx, uri, holdUri, description,
+		appearsOnStatementAs)
+}
+
+// Captures part of a hold, creating a debit for amount cents. The rest of the
+// hold is released, a hold can only be captured once. An amount of zero
+// captures the full hold. The parameters are checked like DebitParams before
+// anything is sent.
+func (c *Client) CaptureHoldAmount(uri, holdUri, description, appearsOnStatementAs string, amount int) (debit *Debit, err error) {
+	return c.CaptureHoldAmountContext(context.Background(), uri, holdUri,
+		description, appearsOnStatementAs, amount)
+}
+
+// CaptureHoldAmountContext is like CaptureHoldAmount but carries the given context.
+func (c *Client) CaptureHoldAmountContext(ctx context.Context, uri, holdUri, description, appearsOnStatementAs string, amount int) (debit *Debit, err error) {
+	return c.CreateNewDebitWithParamsContext(ctx, uri, &DebitParams{
+		Amount:               amount,
+		AppearsOnStatementAs: appearsOnStatementAs,
+		Description:          description,
+		HoldUri:              holdUri,
+	})
+}
+
+// CaptureHoldAmount is a wrapper around DefaultClient.CaptureHoldAmount.
+func CaptureHoldAmount(uri, holdUri, description, appearsOnStatementAs string, amount int) (debit *Debit, err error) {
+	return DefaultClient.CaptureHoldAmount(uri, holdUri, description,
+		appearsOnStatementAs, amount)
+}
+
+// CaptureHoldAmountContext is a wrapper around DefaultClient.CaptureHoldAmountContext.
+func CaptureHoldAmountContext(ctx context.Context, uri, holdUri, description, appearsOnStatementAs string, amount int) (debit *Debit, err error) {
+	return DefaultClient.CaptureHoldAmountContext(ctx, uri, holdUri,
+		description, appearsOnStatementAs, amount)
+}
+
 // Voids a hold. This cancels the hold. After voiding, the hold can no longer be
 // captured. This operation is irreversible.
-func VoidHold(uri, appearsOnStatementAs string, isVoid bool) (hold *Hold, err error) {
+func (c *Client) VoidHold(uri, appearsOnStatementAs string, isVoid bool) (hold *Hold, err error) {
+	return c.VoidHoldContext(context.Background(), uri, appearsOnStatementAs,
+		isVoid)
+}
+
+// VoidHoldContext is like VoidHold but carries the given context.
+func (c *Client) VoidHoldContext(ctx context.Context, uri, appearsOnStatementAs string, isVoid bool) (hold *Hold, err error) {
 	payload := url.Values{}
 
 	addToPayload(payload, "is_void", strconv.FormatBool(isVoid))
 	addToPayload(payload, "appears_on_statement_as", appearsOnStatementAs)
 
 	hold = &Hold{}
-	err = put(uri, payload, hold)
+	err = c.put(ctx, uri, payload, hold)
 
 	return
 }
+
+// VoidHold is a wrapper around DefaultClient.VoidHold.
+func VoidHold(uri, appearsOnStatementAs string, isVoid bool) (hold *Hold, err error) {
+	return DefaultClient.VoidHold(uri, appearsOnStatementAs, isVoid)
+}
+
+// VoidHoldContext is a wrapper around DefaultClient.VoidHoldContext.
+func VoidHoldContext(ctx context.Context, uri, appearsOnStatementAs string, isVoid bool) (hold *Hold, err error) {
+	return DefaultClient.VoidHoldContext(ctx, uri, appearsOnStatementAs, isVoid)
+}
+
+// Iterates over every hold you've previously created, most recent first.
+func (c *Client) IterateHolds(ctx context.Context, options *IterOptions) iter.Seq2[Hold, error] {
+	uri := fmt.Sprintf(holdsUri, c.MarketplaceId)
+
+	return Iterate[Hold](ctx, c, uri, options)
+}
+
+// IterateHolds is a wrapper around DefaultClient.IterateHolds.
+func IterateHolds(ctx context.Context, options *IterOptions) iter.Seq2[Hold, error] {
+	return DefaultClient.IterateHolds(ctx, options)
+}
+
+// Iterates over every hold created against the account whose holds_uri is
+// given, most recent first.
+func (c *Client) IterateHoldsForAccount(ctx context.Context, uri string, options *IterOptions) iter.Seq2[Hold, error] {
+	return Iterate[Hold](ctx, c, uri, options)
+}
+
+// IterateHoldsForAccount is a wrapper around DefaultClient.IterateHoldsForAccount.
+func IterateHoldsForAccount(ctx context.Context, uri string, options *IterOptions) iter.Seq2[Hold, error] {
+	return DefaultClient.IterateHoldsForAccount(ctx, uri, options)
+}
@@ -0,0 +1,293 @@
+package balanced
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultHoldCheckInterval = time.Hour
+	defaultHoldExpiryWarning = 24 * time.Hour
+)
+
+// A HoldManager keeps track of outstanding holds, the ones that were neither
+// captured nor voided yet. Each Check refreshes them from balanced and reports
+// holds that are about to expire, voids holds older than MaxAge, and reports
+// holds that expired without being captured or voided. Holds that cannot be
+// refreshed or voided are reported as failed and checked again next time,
+// except for holds balanced no longer knows of, which stop being tracked.
+//
+//	manager := balanced.NewHoldManager(client)
+//	manager.MaxAge = 3 * 24 * time.Hour
+//	manager.OnReport = func(report *balanced.HoldReport) { ... }
+//	manager.Track(hold)
+//	go manager.Run(ctx)
+//
+// Holds captured or voided through the manager, or found captured or voided by
+// a check, are no longer tracked.
+type HoldManager struct {
+	// Client to manage holds with. When nil DefaultClient is used.
+	Client *Client
+
+	// Holds expiring within this time are reported as expiring, once.
+	// Defaults to 24 hours.
+	ExpiryWarning time.Duration
+	// Holds this old are voided. Zero leaves holds alone until they expire.
+	MaxAge time.Duration
+
+	// Time between checks made by Run. Defaults to an hour.
+	Interval time.Duration
+	// Called by Run with every report that is not empty, including reports
+	// of failed holds.
+	OnReport func(report *HoldReport)
+
+	mu     sync.Mutex
+	holds  map[string]*Hold
+	warned map[string]bool
+	now    func() time.Time
+}
+
+// What a HoldManager check found.
+type HoldReport struct {
+	// Holds that expire within the ExpiryWarning.
+	Expiring []Hold
+	// Holds voided for being older than MaxAge.
+	Voided []Hold
+	// Holds that expired without being captured or voided.
+	Expired []Hold
+	// Holds that could not be refreshed or voided.
+	Failed []HoldError
+}
+
+// Reports whether the check found nothing.
+func (r *HoldReport) Empty() bool {
+	return len(r.Expiring) == 0 && len(r.Voided) == 0 && len(r.Expired) == 0 &&
+		len(r.Failed) == 0
+}
+
+// A tracked hold that a check could not refresh or void.
+type HoldError struct {
+	Uri string
+	Err error
+}
+
+func (e HoldError) Error() string {
+	return fmt.Sprintf("Balanced API: Unable to check hold %v: %v", e.Uri, e.Err)
+}
+
+func (e HoldError) Unwrap() error {
+	return e.Err
+}
+
+// Creates a manager for the holds of client, tracking none yet.
+func NewHoldManager(client *Client) *HoldManager {
+	return &HoldManager{Client: client}
+}
+
+func (m *HoldManager) client() *Client {
+	if m.Client == nil {
+		return DefaultClient
+	}
+
+	return m.Client
+}
+
+func (m *HoldManager) clock() time.Time {
+	if m.now != nil {
+		return m.now()
+	}
+
+	return time.Now()
+}
+
+// Starts tracking a hold. Holds that are already captured or voided are
+// ignored.
+func (m *HoldManager) Track(hold *Hold) {
+	if hold.IsVoid || hold.Debit != nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.holds == nil {
+		m.holds = map[string]*Hold{}
+		m.warned = map[string]bool{}
+	}
+
+	tracked := *hold
+	m.holds[hold.Uri] = &tracked
+}
+
+// Tracks every outstanding hold of the marketplace, i.e. after a restart.
+// Returns the number of holds tracked.
+func (m *HoldManager) TrackOutstanding(ctx context.Context) (int, error) {
+	now := m.clock()
+	count := 0
+
+	for hold, err := range m.client().IterateHolds(ctx, nil) {
+		if err != nil {
+			return count, err
+		}
+
+		if hold.IsVoid || hold.Debit != nil || holdExpired(&hold, now) {
+			continue
+		}
+
+		m.Track(&hold)
+		count++
+	}
+
+	return count, nil
+}
+
+// Reports whether a hold can no longer be captured at now.
+func holdExpired(hold *Hold, now time.Time) bool {
+	return !hold.ExpiresAt.IsZero() && !now.Before(hold.ExpiresAt)
+}
+
+func (m *HoldManager) untrack(uri string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.holds, uri)
+	delete(m.warned, uri)
+}
+
+// Returns the holds being tracked, as of the last check.
+func (m *HoldManager) Outstanding() []Hold {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	holds := make([]Hold, 0, len(m.holds))
+	for _, hold := range m.holds {
+		holds = append(holds, *hold)
+	}
+
+	return holds
+}
+
+// Captures amount cents of a tracked hold, or all of it if amount is zero,
+// and stops tracking it.
+func (m *HoldManager) Capture(ctx context.Context, holdUri string, amount int) (*Debit, error) {
+	c := m.client()
+	uri := fmt.Sprintf(debitsUri, c.MarketplaceId)
+
+	debit, err := c.CaptureHoldAmountContext(ctx, uri, holdUri, "", "", amount)
+	if err != nil {
+		return nil, err
+	}
+	m.untrack(holdUri)
+
+	return debit, nil
+}
+
+// Voids a tracked hold and stops tracking it.
+func (m *HoldManager) Void(ctx context.Context, holdUri string) (*Hold, error) {
+	hold, err := m.client().VoidHoldContext(ctx, holdUri, "", true)
+	if err != nil {
+		return nil, err
+	}
+	m.untrack(holdUri)
+
+	return hold, nil
+}
+
+// Refreshes every tracked hold from balanced, voiding the ones older than
+// MaxAge, and reports what it found. A hold that fails does not stop the check,
+// it is reported in Failed. Returns ctx.Err() once ctx is done, along with what
+// was found so far.
+func (m *HoldManager) Check(ctx context.Context) (*HoldReport, error) {
+	report := &HoldReport{}
+	now := m.clock()
+
+	expiryWarning := m.ExpiryWarning
+	if expiryWarning <= 0 {
+		expiryWarning = defaultHoldExpiryWarning
+	}
+
+	for _, tracked := range m.Outstanding() {
+		hold, err := m.client().RetrieveHoldContext(ctx, tracked.Uri)
+		if err != nil {
+			if ctx.Err() != nil {
+				return report, ctx.Err()
+			}
+			if errors.Is(err, ErrNotFound) {
+				m.untrack(tracked.Uri)
+			}
+			report.Failed = append(report.Failed, HoldError{tracked.Uri, err})
+			continue
+		}
+
+		switch {
+		case hold.IsVoid || hold.Debit != nil:
+			// Settled without the manager
+			m.untrack(hold.Uri)
+
+		case holdExpired(hold, now):
+			m.untrack(hold.Uri)
+			report.Expired = append(report.Expired, *hold)
+
+		case m.MaxAge > 0 && now.Sub(hold.CreatedAt) >= m.MaxAge:
+			voided, err := m.Void(ctx, hold.Uri)
+			if err != nil {
+				if ctx.Err() != nil {
+					return report, ctx.Err()
+				}
+				report.Failed = append(report.Failed, HoldError{hold.Uri, err})
+				continue
+			}
+			report.Voided = append(report.Voided, *voided)
+
+		default:
+			m.mu.Lock()
+			if _, ok := m.holds[hold.Uri]; ok {
+				m.holds[hold.Uri] = hold
+			}
+			warn := !hold.ExpiresAt.IsZero() && !m.warned[hold.Uri] &&
+				hold.ExpiresAt.Sub(now) <= expiryWarning
+			if warn {
+				m.warned[hold.Uri] = true
+			}
+			m.mu.Unlock()
+
+			if warn {
+				report.Expiring = append(report.Expiring, *hold)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// Checks the tracked holds every Interval until ctx is done, handing reports
+// to OnReport. Holds that fail are reported and checked again on the next
+// tick. Returns ctx.Err().
+func (m *HoldManager) Run(ctx context.Context) error {
+	interval := m.Interval
+	if interval <= 0 {
+		interval = defaultHoldCheckInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		report, err := m.Check(ctx)
+		if !report.Empty() && m.OnReport != nil {
+			m.OnReport(report)
+		}
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
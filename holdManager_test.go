@@ -0,0 +1,237 @@
+package balanced
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/nimajalali/balanced-go/balancedtest"
+)
+
+func createHold(t *testing.T, account *Account, amount int) *Hold {
+	hold, err := CreateNewHold(account.HoldsUri, "", "", "", "", "", amount, nil)
+	if err != nil {
+		t.Fatalf("Failed to create hold: %v", err)
+	}
+
+	return hold
+}
+
+func TestHoldManager(t *testing.T) {
+	ctx := context.Background()
+	account := createAccountWithCard(t, testVisaCard)
+
+	captured := createHold(t, account, 4000)
+	voided := createHold(t, account, 1000)
+	outstanding := createHold(t, account, 2500)
+
+	start := time.Now()
+	manager := NewHoldManager(nil)
+	manager.now = func() time.Time { return start }
+
+	for _, hold := range []*Hold{captured, voided, outstanding} {
+		manager.Track(hold)
+	}
+
+	debit, err := manager.Capture(ctx, captured.Uri, 1500)
+	if err != nil {
+		t.Fatalf("Failed to capture hold: %v", err)
+	}
+	if debit.Amount != 1500 {
+		t.Fatalf("Invalid debit captured: %v", debit)
+	}
+
+	// Voided without the manager
+	if _, err := VoidHold(voided.Uri, "", true); err != nil {
+		t.Fatalf("Failed to void hold: %v", err)
+	}
+
+	report, err := manager.Check(ctx)
+	if err != nil {
+		t.Fatalf("Failed to check holds: %v", err)
+	}
+	if !report.Empty() {
+		t.Fatalf("Expected an empty report, got %v", report)
+	}
+
+	holds := manager.Outstanding()
+	if len(holds) != 1 || holds[0].Uri != outstanding.Uri {
+		t.Fatalf("Expected a single outstanding hold, got %v", holds)
+	}
+
+	// Expiring holds are reported once
+	manager.now = func() time.Time { return outstanding.ExpiresAt.Add(-time.Hour) }
+	for i := 0; i < 2; i++ {
+		report, err = manager.Check(ctx)
+		if err != nil {
+			t.Fatalf("Failed to check holds: %v", err)
+		}
+
+		expiring := 1
+		if i > 0 {
+			expiring = 0
+		}
+		if len(report.Expiring) != expiring {
+			t.Fatalf("Expected %v expiring holds, got %v", expiring, report)
+		}
+	}
+
+	manager.now = func() time.Time { return outstanding.ExpiresAt }
+	report, err = manager.Check(ctx)
+	if err != nil {
+		t.Fatalf("Failed to check holds: %v", err)
+	}
+
+	if len(report.Expired) != 1 || report.Expired[0].Uri != outstanding.Uri {
+		t.Fatalf("Expected the outstanding hold to expire, got %v", report)
+	}
+	if len(manager.Outstanding()) != 0 {
+		t.Fatalf("Expected no outstanding holds, got %v", manager.Outstanding())
+	}
+}
+
+func TestHoldManagerMaxAge(t *testing.T) {
+	ctx := context.Background()
+	// TrackOutstanding picks up every hold, so keep other tests' holds away
+	server := balancedtest.NewServer()
+	defer server.Close()
+	client := NewClient(server.URL, server.ApiKey, server.MarketplaceId)
+
+	account, err := client.CreateAccount()
+	if err != nil {
+		t.Fatalf("Unable to create account: %v", err)
+	}
+	card, err := client.TokenizeCard(testExpirationYear, 1, testMasterCard, "",
+		"", "", "", "", "", "", "", nil)
+	if err != nil {
+		t.Fatalf("Unable to create card: %v", err)
+	}
+	if _, err := client.AddCardToAccount(account.Uri, card.Uri); err != nil {
+		t.Fatalf("Unable to add card to account: %v", err)
+	}
+	hold, err := client.CreateNewHold(account.HoldsUri, "", "", "", "", "", 3000, nil)
+	if err != nil {
+		t.Fatalf("Failed to create hold: %v", err)
+	}
+
+	manager := NewHoldManager(client)
+	manager.MaxAge = 2 * 24 * time.Hour
+	manager.now = func() time.Time { return hold.CreatedAt.Add(manager.MaxAge) }
+
+	count, err := manager.TrackOutstanding(ctx)
+	if err != nil {
+		t.Fatalf("Failed to track outstanding holds: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("Expected the outstanding hold to be tracked, got %v", count)
+	}
+
+	reports := make(chan *HoldReport, 1)
+	manager.OnReport = func(report *HoldReport) { reports <- report }
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() { done <- manager.Run(runCtx) }()
+
+	report := <-reports
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("Expected run to be canceled, got %v", err)
+	}
+
+	found := false
+	for _, voided := range report.Voided {
+		found = found || (voided.Uri == hold.Uri && voided.IsVoid)
+	}
+	if !found {
+		t.Fatalf("Expected the hold to be voided, got %v", report)
+	}
+
+	hold, err = client.RetrieveHold(hold.Uri)
+	if err != nil || !hold.IsVoid {
+		t.Fatalf("Expected the hold to be void, got %v %v", hold, err)
+	}
+}
+
+func TestHoldManagerFailures(t *testing.T) {
+	ctx := context.Background()
+	account := createAccountWithCard(t, testVisaCard)
+	flaky := createHold(t, account, 1000)
+	expiring := createHold(t, account, 2000)
+	missing := &Hold{Uri: flaky.Uri + "0"}
+
+	// Balanced is unavailable for one of the holds
+	client := NewClient(DefaultClient.ApiRoot, DefaultClient.ApiKey,
+		DefaultClient.MarketplaceId)
+	client.Use(func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			if req.Path == flaky.Uri {
+				return &Response{StatusCode: http.StatusServiceUnavailable,
+					Body: []byte(`{}`)}, nil
+			}
+
+			return next(ctx, req)
+		}
+	})
+
+	manager := NewHoldManager(client)
+	manager.now = func() time.Time { return expiring.ExpiresAt.Add(-time.Hour) }
+	for _, hold := range []*Hold{flaky, missing, expiring} {
+		manager.Track(hold)
+	}
+
+	report, err := manager.Check(ctx)
+	if err != nil {
+		t.Fatalf("Failed to check holds: %v", err)
+	}
+
+	// The other holds are still checked
+	if len(report.Expiring) != 1 || report.Expiring[0].Uri != expiring.Uri {
+		t.Fatalf("Expected the expiring hold to be reported, got %v", report)
+	}
+
+	failed := map[string]error{}
+	for _, holdErr := range report.Failed {
+		failed[holdErr.Uri] = holdErr
+	}
+	if len(failed) != 2 || failed[flaky.Uri] == nil ||
+		!errors.Is(failed[missing.Uri], ErrNotFound) {
+		t.Fatalf("Expected the flaky and missing holds to fail, got %v", report.Failed)
+	}
+
+	// Missing holds are no longer tracked, flaky ones are checked again
+	tracked := map[string]bool{}
+	for _, hold := range manager.Outstanding() {
+		tracked[hold.Uri] = true
+	}
+	if len(tracked) != 2 || !tracked[flaky.Uri] || !tracked[expiring.Uri] {
+		t.Fatalf("Invalid outstanding holds: %v", tracked)
+	}
+
+	// Run keeps checking after failures
+	reports := make(chan *HoldReport, 2)
+	manager.Interval = time.Millisecond
+	manager.OnReport = func(report *HoldReport) {
+		select {
+		case reports <- report:
+		default:
+		}
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() { done <- manager.Run(runCtx) }()
+
+	for i := 0; i < 2; i++ {
+		if report := <-reports; len(report.Failed) != 1 {
+			t.Fatalf("Expected the flaky hold to fail again, got %v", report)
+		}
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("Expected run to be canceled, got %v", err)
+	}
+}
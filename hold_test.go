@@ -0,0 +1,107 @@
+package balanced
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHold(t *testing.T) {
+	account := createAccountWithCard(t, testMasterCard)
+
+	hold, err := CreateNewHold(account.HoldsUri, "", "", "Reservation", "",
+		"", 2500, nil)
+	if err != nil {
+		t.Fatalf("Failed to create hold: %v", err)
+	}
+
+	if hold.Amount != 2500 || hold.ExpiresAt.IsZero() {
+		t.Fatalf("Invalid hold created: %v", hold)
+	}
+
+	debit, err := CaptureHold(account.DebitsUri, hold.Uri, "Captured", "")
+	if err != nil {
+		t.Fatalf("Failed to capture hold: %v", err)
+	}
+
+	if debit.Amount != hold.Amount || debit.Hold.Uri != hold.Uri {
+		t.Fatalf("Invalid debit captured: %v", debit)
+	}
+
+	// A captured hold can no longer be voided
+	if _, err := VoidHold(hold.Uri, "", true); err == nil {
+		t.Fatal("Expected voiding a captured hold to fail")
+	}
+}
+
+func TestVoidHold(t *testing.T) {
+	account := createAccountWithCard(t, testVisaCard)
+
+	hold, err := CreateNewHold(account.HoldsUri, "", "", "", "", "", 900, nil)
+	if err != nil {
+		t.Fatalf("Failed to create hold: %v", err)
+	}
+
+	hold, err = VoidHold(hold.Uri, "", true)
+	if err != nil {
+		t.Fatalf("Failed to void hold: %v", err)
+	}
+
+	if !hold.IsVoid {
+		t.Fatal("Failed to void hold")
+	}
+
+	list, err := ListAllHoldsForAccount(account.HoldsUri, 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to retrieve list of holds: %v", err)
+	}
+
+	if len(list.Items) != 1 || !list.Items[0].IsVoid {
+		t.Fatalf("Invalid list of holds: %v", list)
+	}
+}
+
+func TestCaptureHoldAmount(t *testing.T) {
+	account := createAccountWithCard(t, testVisaCard)
+
+	hold, err := CreateNewHold(account.HoldsUri, "", "", "", "", "", 5000, nil)
+	if err != nil {
+		t.Fatalf("Failed to create hold: %v", err)
+	}
+
+	debit, err := CaptureHoldAmount(account.DebitsUri, hold.Uri, "Shipped",
+		"", 3500)
+	if err != nil {
+		t.Fatalf("Failed to capture hold: %v", err)
+	}
+
+	if debit.Amount != 3500 || debit.Hold.Uri != hold.Uri {
+		t.Fatalf("Invalid debit captured: %v", debit)
+	}
+
+	hold, err = RetrieveHold(hold.Uri)
+	if err != nil {
+		t.Fatalf("Failed to retrieve hold: %v", err)
+	}
+
+	if hold.Debit == nil || hold.Debit.Uri != debit.Uri ||
+		hold.TransactionStatus() != "captured" {
+		t.Fatalf("Hold does not reference its debit: %v", hold)
+	}
+
+	// Negative amounts are rejected before anything is sent
+	_, err = CaptureHoldAmount(account.DebitsUri, hold.Uri, "", "", -1)
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("Expected a validation error, got %v", err)
+	}
+
+	// Capturing more than the hold is rejected by balanced
+	hold, err = CreateNewHold(account.HoldsUri, "", "", "", "", "", 5000, nil)
+	if err != nil {
+		t.Fatalf("Failed to create hold: %v", err)
+	}
+
+	_, err = CaptureHoldAmount(account.DebitsUri, hold.Uri, "", "", 5001)
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("Expected over-capture to be rejected, got %v", err)
+	}
+}
@@ -1,7 +1,16 @@
 package balanced
 
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/url"
+	"strings"
+)
+
 const (
-	marketplaceUri = "/v1/marketplaces"
+	marketplaceUri   = "/v1/marketplaces"
+	marketplaceIdUri = marketplaceUri + "/%v"
 )
 
 type Marketplace struct {
@@ -25,3 +34,183 @@ type Marketplace struct {
 	RefundsUri          string   `json:"refunds_uri,omitempty"`
 	DebitsUri           string   `json:"debits_uri,omitempty"`
 }
+
+type ListOfMarketplaces = Page[Marketplace]
+
+// Parameters for updating a marketplace with UpdateMarketplace. Empty fields
+// are left unchanged.
+type MarketplaceParams struct {
+	Name                string
+	SupportEmailAddress string
+	SupportPhoneNumber  string
+	// Domain of the marketplace's website, i.e. example.com.
+	DomainUrl string
+	Meta      MetaType
+}
+
+// Checks the parameters without contacting balanced.
+func (p *MarketplaceParams) Validate() error {
+	v := &ValidationError{}
+
+	if len(p.SupportEmailAddress) != 0 &&
+		!strings.Contains(p.SupportEmailAddress, "@") {
+		v.add("support_email_address", "must be an email address, got %q",
+			p.SupportEmailAddress)
+	}
+	if strings.ContainsAny(p.DomainUrl, " \t\r\n") {
+		v.add("domain_url", "must be a domain, got %q", p.DomainUrl)
+	}
+
+	return v.err()
+}
+
+func (p *MarketplaceParams) payload() url.Values {
+	payload := url.Values{}
+
+	addToPayload(payload, "name", p.Name)
+	addToPayload(payload, "support_email_address", p.SupportEmailAddress)
+	addToPayload(payload, "support_phone_number", p.SupportPhoneNumber)
+	addToPayload(payload, "domain_url", p.DomainUrl)
+	addMetaToPayload(payload, p.Meta)
+
+	return payload
+}
+
+// Retrieves the details of a marketplace. Use RetrieveCurrentMarketplace for
+// the client's own marketplace.
+func (c *Client) RetrieveMarketplace(uri string) (marketplace *Marketplace, err error) {
+	return c.RetrieveMarketplaceContext(context.Background(), uri)
+}
+
+// RetrieveMarketplaceContext is like RetrieveMarketplace but carries the given context.
+func (c *Client) RetrieveMarketplaceContext(ctx context.Context, uri string) (marketplace *Marketplace, err error) {
+	marketplace = &Marketplace{}
+	err = c.get(ctx, uri, nil, marketplace)
+
+	return
+}
+
+// RetrieveMarketplace is a wrapper around DefaultClient.RetrieveMarketplace.
+func RetrieveMarketplace(uri string) (marketplace *Marketplace, err error) {
+	return DefaultClient.RetrieveMarketplace(uri)
+}
+
+// RetrieveMarketplaceContext is a wrapper around DefaultClient.RetrieveMarketplaceContext.
+func RetrieveMarketplaceContext(ctx context.Context, uri string) (marketplace *Marketplace, err error) {
+	return DefaultClient.RetrieveMarketplaceContext(ctx, uri)
+}
+
+// Retrieves the details of the marketplace the client is configured for.
+func (c *Client) RetrieveCurrentMarketplace() (marketplace *Marketplace, err error) {
+	return c.RetrieveCurrentMarketplaceContext(context.Background())
+}
+
+// RetrieveCurrentMarketplaceContext is like RetrieveCurrentMarketplace but carries the given context.
+func (c *Client) RetrieveCurrentMarketplaceContext(ctx context.Context) (marketplace *Marketplace, err error) {
+	uri := fmt.Sprintf(marketplaceIdUri, c.MarketplaceId)
+
+	return c.RetrieveMarketplaceContext(ctx, uri)
+}
+
+// RetrieveCurrentMarketplace is a wrapper around DefaultClient.RetrieveCurrentMarketplace.
+func RetrieveCurrentMarketplace() (marketplace *Marketplace, err error) {
+	return DefaultClient.RetrieveCurrentMarketplace()
+}
+
+// RetrieveCurrentMarketplaceContext is a wrapper around DefaultClient.RetrieveCurrentMarketplaceContext.
+func RetrieveCurrentMarketplaceContext(ctx context.Context) (marketplace *Marketplace, err error) {
+	return DefaultClient.RetrieveCurrentMarketplaceContext(ctx)
+}
+
+// Returns a list of the marketplaces the api key has access to.
+func (c *Client) ListMarketplaces(limit, offset int) (listOfMarketplaces *ListOfMarketplaces, err error) {
+	return c.ListMarketplacesContext(context.Background(), limit, offset)
+}
+
+// ListMarketplacesContext is like ListMarketplaces but carries the given context.
+func (c *Client) ListMarketplacesContext(ctx context.Context, limit, offset int) (listOfMarketplaces *ListOfMarketplaces, err error) {
+	payload := defaultPayload(limit, offset)
+
+	listOfMarketplaces = &ListOfMarketplaces{}
+	err = c.get(ctx, marketplaceUri, payload, listOfMarketplaces)
+
+	return
+}
+
+// ListMarketplaces is a wrapper around DefaultClient.ListMarketplaces.
+func ListMarketplaces(limit, offset int) (listOfMarketplaces *ListOfMarketplaces, err error) {
+	return DefaultClient.ListMarketplaces(limit, offset)
+}
+
+// ListMarketplacesContext is a wrapper around DefaultClient.ListMarketplacesContext.
+func ListMarketplacesContext(ctx context.Context, limit, offset int) (listOfMarketplaces *ListOfMarketplaces, err error) {
+	return DefaultClient.ListMarketplacesContext(ctx, limit, offset)
+}
+
+// Updates the name, support contacts, domain or meta of a marketplace. The
+// parameters are validated before the request is sent.
+func (c *Client) UpdateMarketplace(uri string, params *MarketplaceParams) (marketplace *Marketplace, err error) {
+	return c.UpdateMarketplaceContext(context.Background(), uri, params)
+}
+
+// UpdateMarketplaceContext is like UpdateMarketplace but carries the given context.
+func (c *Client) UpdateMarketplaceContext(ctx context.Context, uri string, params *MarketplaceParams) (marketplace *Marketplace, err error) {
+	if params == nil {
+		params = &MarketplaceParams{}
+	}
+
+	if err = params.Validate(); err != nil {
+		return nil, err
+	}
+
+	marketplace = &Marketplace{}
+	err = c.put(ctx, uri, params.payload(), marketplace)
+
+	return
+}
+
+// UpdateMarketplace is a wrapper around DefaultClient.UpdateMarketplace.
+func UpdateMarketplace(uri string, params *MarketplaceParams) (marketplace *Marketplace, err error) {
+	return DefaultClient.UpdateMarketplace(uri, params)
+}
+
+// UpdateMarketplaceContext is a wrapper around DefaultClient.UpdateMarketplaceContext.
+func UpdateMarketplaceContext(ctx context.Context, uri string, params *MarketplaceParams) (marketplace *Marketplace, err error) {
+	return DefaultClient.UpdateMarketplaceContext(ctx, uri, params)
+}
+
+// Returns the funds currently held in escrow by the client's marketplace, in
+// cents. Debits add to the escrow balance, credits and refunds take from it.
+func (c *Client) EscrowBalance() (inEscrow int, err error) {
+	return c.EscrowBalanceContext(context.Background())
+}
+
+// EscrowBalanceContext is like EscrowBalance but carries the given context.
+func (c *Client) EscrowBalanceContext(ctx context.Context) (inEscrow int, err error) {
+	marketplace, err := c.RetrieveCurrentMarketplaceContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return marketplace.InEscrow, nil
+}
+
+// EscrowBalance is a wrapper around DefaultClient.EscrowBalance.
+func EscrowBalance() (inEscrow int, err error) {
+	return DefaultClient.EscrowBalance()
+}
+
+// EscrowBalanceContext is a wrapper around DefaultClient.EscrowBalanceContext.
+func EscrowBalanceContext(ctx context.Context) (inEscrow int, err error) {
+	return DefaultClient.EscrowBalanceContext(ctx)
+}
+
+// Iterates over every marketplace the api key has access to.
+func (c *Client) IterateMarketplaces(ctx context.Context, options *IterOptions) iter.Seq2[Marketplace, error] {
+	return Iterate[Marketplace](ctx, c, marketplaceUri, options)
+}
+
+// IterateMarketplaces is a wrapper around DefaultClient.IterateMarketplaces.
+func IterateMarketplaces(ctx context.Context, options *IterOptions) iter.Seq2[Marketplace, error] {
+	return DefaultClient.IterateMarketplaces(ctx, options)
+}
@@ -0,0 +1,124 @@
+package balanced
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRetrieveMarketplace(t *testing.T) {
+	marketplace, err := RetrieveCurrentMarketplace()
+	if err != nil {
+		t.Fatalf("Unable to retrieve marketplace: %v", err)
+	}
+
+	if marketplace.Id != DefaultClient.MarketplaceId {
+		t.Fatalf("Incorrect marketplace retrieved: %v", marketplace)
+	}
+
+	retrieved, err := RetrieveMarketplace(marketplace.Uri)
+	if err != nil {
+		t.Fatalf("Unable to retrieve marketplace by uri: %v", err)
+	}
+
+	if retrieved.Uri != marketplace.Uri || retrieved.DebitsUri != marketplace.DebitsUri {
+		t.Fatalf("Incorrect marketplace retrieved by uri: %v", retrieved)
+	}
+}
+
+func TestListMarketplaces(t *testing.T) {
+	list, err := ListMarketplaces(10, 0)
+	if err != nil {
+		t.Fatalf("Unable to list marketplaces: %v", err)
+	}
+
+	found := false
+	for _, marketplace := range list.Items {
+		found = found || marketplace.Id == DefaultClient.MarketplaceId
+	}
+	if !found {
+		t.Fatalf("Marketplace missing from list: %v", list.Items)
+	}
+
+	count := 0
+	for _, err := range IterateMarketplaces(context.Background(), nil) {
+		if err != nil {
+			t.Fatalf("Unable to iterate over marketplaces: %v", err)
+		}
+		count++
+	}
+
+	if count != list.Total {
+		t.Fatalf("Expected %v marketplaces, iterated over %v", list.Total, count)
+	}
+}
+
+func TestUpdateMarketplace(t *testing.T) {
+	marketplace, err := RetrieveCurrentMarketplace()
+	if err != nil {
+		t.Fatalf("Unable to retrieve marketplace: %v", err)
+	}
+
+	updated, err := UpdateMarketplace(marketplace.Uri, &MarketplaceParams{
+		Name:                "Skripts4Kids",
+		SupportEmailAddress: "help@skripts4kids.com",
+		SupportPhoneNumber:  "+14089999999",
+		DomainUrl:           "skripts4kids.com",
+		Meta:                MetaType{"region": "us"},
+	})
+	if err != nil {
+		t.Fatalf("Unable to update marketplace: %v", err)
+	}
+
+	if updated.Name != "Skripts4Kids" ||
+		updated.SupportEmailAddress != "help@skripts4kids.com" ||
+		updated.SupportPhoneNumber != "+14089999999" ||
+		updated.DomainUrl != "skripts4kids.com" || updated.Meta["region"] != "us" {
+		t.Fatalf("Invalid marketplace updated: %v", updated)
+	}
+
+	// Fields that are not given are left unchanged
+	updated, err = UpdateMarketplace(marketplace.Uri, &MarketplaceParams{
+		Name: "Skripts4Kids Inc.",
+	})
+	if err != nil {
+		t.Fatalf("Unable to update marketplace: %v", err)
+	}
+
+	if updated.Name != "Skripts4Kids Inc." || updated.DomainUrl != "skripts4kids.com" {
+		t.Fatalf("Invalid marketplace updated: %v", updated)
+	}
+}
+
+func TestUpdateMarketplaceValidation(t *testing.T) {
+	client := newStaticClient(500, `{}`)
+
+	_, err := client.UpdateMarketplace("/v1/marketplaces/MP1", &MarketplaceParams{
+		SupportEmailAddress: "skripts4kids.com",
+		DomainUrl:           "skripts 4 kids",
+	})
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Errors) != 2 {
+		t.Fatalf("Expected 2 invalid parameters, got %v", err)
+	}
+}
+
+func TestEscrowBalance(t *testing.T) {
+	before, err := EscrowBalance()
+	if err != nil {
+		t.Fatalf("Unable to read escrow balance: %v", err)
+	}
+
+	account := createAccountWithCard(t, testVisaCard)
+	debit := createNewDebit(t, account)
+
+	after, err := EscrowBalance()
+	if err != nil {
+		t.Fatalf("Unable to read escrow balance: %v", err)
+	}
+
+	if after != before+debit.Amount {
+		t.Fatalf("Expected %v in escrow, got %v", before+debit.Amount, after)
+	}
+}
@@ -0,0 +1,75 @@
+package balanced
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// An outgoing api request as seen by middleware. Path is relative to the
+// client's ApiRoot and Payload has not been encoded yet. For GET requests the
+// payload is sent as the query string, otherwise as a form encoded body.
+type Request struct {
+	Method  string
+	Path    string
+	Payload url.Values
+	Header  http.Header
+}
+
+// A raw api response as seen by middleware, before the body is decoded.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// A Handler sends a request and returns the raw response.
+type Handler func(ctx context.Context, req *Request) (*Response, error)
+
+// Middleware wraps a Handler. It may inspect or modify the request before
+// calling next, inspect or modify the response after, or answer the request
+// itself without calling next at all.
+type Middleware func(next Handler) Handler
+
+// Creates middleware that calls fn before every request is sent. Returning an
+// error aborts the request.
+func InterceptRequest(fn func(ctx context.Context, req *Request) error) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			if err := fn(ctx, req); err != nil {
+				return nil, err
+			}
+
+			return next(ctx, req)
+		}
+	}
+}
+
+// Creates middleware that calls fn with every raw response, before it is
+// decoded. Returning an error fails the request.
+func InterceptResponse(fn func(ctx context.Context, req *Request, resp *Response) error) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			resp, err := next(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := fn(ctx, req, resp); err != nil {
+				return nil, err
+			}
+
+			return resp, nil
+		}
+	}
+}
+
+// Builds the handler chain for the client's middleware.
+func (c *Client) handler() Handler {
+	handler := Handler(c.send)
+	for i := len(c.Middleware) - 1; i >= 0; i-- {
+		handler = c.Middleware[i](handler)
+	}
+
+	return handler
+}
@@ -0,0 +1,56 @@
+package balanced
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	var calls []string
+
+	client := NewClient("http://balanced.invalid", "ak-test-key", "TEST-MP123")
+	client.Use(
+		InterceptRequest(func(ctx context.Context, req *Request) error {
+			calls = append(calls, "request "+req.Method+" "+req.Path+" "+
+				req.Payload.Encode())
+			return nil
+		}),
+		InterceptResponse(func(ctx context.Context, req *Request, resp *Response) error {
+			calls = append(calls, "response "+string(resp.Body))
+			return nil
+		}),
+		// Answer every request without touching the network
+		func(next Handler) Handler {
+			return func(ctx context.Context, req *Request) (*Response, error) {
+				if !strings.HasPrefix(req.Header.Get("Authorization"), "Basic ") {
+					t.Fatalf("Missing authorization header: %v", req.Header)
+				}
+
+				return &Response{
+					StatusCode: http.StatusOK,
+					Body:       []byte(`{"uri": "/v1/marketplaces/TEST-MP123/cards/CC1"}`),
+				}, nil
+			}
+		},
+	)
+
+	card, err := client.UpdateCard("/v1/marketplaces/TEST-MP123/cards/CC1",
+		MetaType{"testKey": "testValue"})
+	if err != nil {
+		t.Fatalf("Failed to update card: %v", err)
+	}
+
+	if card.Uri != "/v1/marketplaces/TEST-MP123/cards/CC1" {
+		t.Fatalf("Invalid card decoded: %v", card)
+	}
+
+	expected := []string{
+		"request PUT /v1/marketplaces/TEST-MP123/cards/CC1 meta%5BtestKey%5D=testValue",
+		`response {"uri": "/v1/marketplaces/TEST-MP123/cards/CC1"}`,
+	}
+	if strings.Join(calls, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("Unexpected middleware calls: %v", calls)
+	}
+}
@@ -0,0 +1,132 @@
+package balanced
+
+import (
+	"context"
+	"iter"
+)
+
+const (
+	defaultPageSize = 25
+)
+
+// A single page of a list resource, i.e. the response of ListAllDebits.
+type Page[T any] struct {
+	FirstUri    string `json:"first_uri,omitempty"`
+	Items       []T    `json:"items,omitempty"`
+	LastUri     string `json:"last_uri,omitempty"`
+	Limit       int    `json:"limit,omitempty"`
+	NextUri     string `json:"next_uri,omitempty"`
+	Offset      int    `json:"offset,omitempty"`
+	PreviousUri string `json:"previous_uri,omitempty"`
+	Total       int    `json:"total,omitempty"`
+	Uri         string `json:"uri,omitempty"`
+}
+
+// Reports whether there is a page after this one.
+func (p *Page[T]) HasNext() bool {
+	return len(p.NextUri) != 0
+}
+
+// Options for iterating over a list resource. A nil *IterOptions uses the
+// defaults.
+type IterOptions struct {
+	// Number of items requested per page. Defaults to 25.
+	PageSize int
+	// Stop after this many items. Zero means iterate over every item.
+	MaxItems int
+	// Filters and sort order of the items. Its page is ignored.
+	Query *Query
+}
+
+func (o *IterOptions) pageSize() int {
+	pageSize := defaultPageSize
+	if o != nil && o.PageSize > 0 {
+		pageSize = o.PageSize
+	}
+
+	if max := o.maxItems(); max > 0 && max < pageSize {
+		pageSize = max
+	}
+
+	return pageSize
+}
+
+func (o *IterOptions) query() *Query {
+	if o == nil {
+		return nil
+	}
+
+	return o.Query
+}
+
+func (o *IterOptions) maxItems() int {
+	if o == nil {
+		return 0
+	}
+
+	return o.MaxItems
+}
+
+// Returns an iterator over every item of the list resource at uri, following
+// NextUri from page to page. Iteration stops at the first error, which is
+// yielded along with the zero value of T.
+//
+//	for debit, err := range balanced.Iterate[balanced.Debit](ctx, client, uri, nil) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func Iterate[T any](ctx context.Context, c *Client, uri string, options *IterOptions) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		next := uri
+		payload := options.query().filterValues()
+		for key, values := range defaultPayload(options.pageSize(), 0) {
+			payload[key] = values
+		}
+		maxItems := options.maxItems()
+		count := 0
+
+		for len(next) != 0 {
+			page := &Page[T]{}
+			if err := c.get(ctx, next, payload, page); err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+
+				count++
+				if maxItems > 0 && count >= maxItems {
+					return
+				}
+			}
+
+			// An empty page means the end, even if balanced says otherwise
+			if len(page.Items) == 0 {
+				return
+			}
+
+			// The next uri already carries limit and offset
+			next, payload = page.NextUri, nil
+		}
+	}
+}
+
+// Returns the page of the list resource at uri selected by query, with its
+// filters and sort order applied.
+//
+//	query := balanced.NewQuery().Status("failed")
+//	list, err := balanced.List[balanced.Debit](ctx, client, account.DebitsUri, query)
+func List[T any](ctx context.Context, c *Client, uri string, query *Query) (*Page[T], error) {
+	page := &Page[T]{}
+	if err := c.get(ctx, uri, query.payload(), page); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
@@ -0,0 +1,102 @@
+package balanced
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+// Creates a client that serves total debits, paginated like balanced does.
+func newPagingClient(total int, requests *int) *Client {
+	client := NewClient("http://balanced.invalid", "ak-test-key", "TEST-MP123")
+	client.Use(func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			*requests++
+
+			u, err := url.Parse(req.Path)
+			if err != nil {
+				return nil, err
+			}
+			query := u.Query()
+			for key, values := range req.Payload {
+				query[key] = values
+			}
+			limit, _ := strconv.Atoi(query.Get("limit"))
+			offset, _ := strconv.Atoi(query.Get("offset"))
+
+			page := ListOfDebits{Limit: limit, Offset: offset, Total: total}
+			for i := offset; i < offset+limit && i < total; i++ {
+				page.Items = append(page.Items, Debit{Id: strconv.Itoa(i)})
+			}
+			if offset+limit < total {
+				page.NextUri = fmt.Sprintf("%v?limit=%v&offset=%v", u.Path,
+					limit, offset+limit)
+			}
+
+			body, err := json.Marshal(page)
+			if err != nil {
+				return nil, err
+			}
+
+			return &Response{StatusCode: http.StatusOK, Body: body}, nil
+		}
+	})
+
+	return client
+}
+
+func TestIterateDebits(t *testing.T) {
+	requests := 0
+	client := newPagingClient(7, &requests)
+
+	var ids []string
+	options := &IterOptions{PageSize: 3}
+	for debit, err := range client.IterateDebits(context.Background(), options) {
+		if err != nil {
+			t.Fatalf("Failed to iterate debits: %v", err)
+		}
+		ids = append(ids, debit.Id)
+	}
+
+	if fmt.Sprint(ids) != "[0 1 2 3 4 5 6]" || requests != 3 {
+		t.Fatalf("Unexpected debits %v after %v requests", ids, requests)
+	}
+}
+
+func TestIterateMaxItems(t *testing.T) {
+	requests := 0
+	client := newPagingClient(7, &requests)
+
+	count := 0
+	options := &IterOptions{PageSize: 3, MaxItems: 4}
+	for _, err := range client.IterateDebits(context.Background(), options) {
+		if err != nil {
+			t.Fatalf("Failed to iterate debits: %v", err)
+		}
+		count++
+	}
+
+	if count != 4 || requests != 2 {
+		t.Fatalf("Expected 4 debits in 2 requests, got %v in %v", count, requests)
+	}
+}
+
+func TestIterateStopsOnError(t *testing.T) {
+	client := newStaticClient(http.StatusNotFound, `{"status": "Not Found"}`)
+
+	count := 0
+	for _, err := range client.IterateEvents(context.Background(), nil) {
+		count++
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+	}
+
+	if count != 1 {
+		t.Fatalf("Expected a single error, got %v items", count)
+	}
+}
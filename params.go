@@ -0,0 +1,79 @@
+package balanced
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Longest soft descriptor balanced accepts.
+	maxAppearsOnStatementAs = 22
+)
+
+// The *Params types are the named-field counterparts of the long positional
+// parameter lists taken by TokenizeCard, CreateNewDebit and friends. Each has
+// a Validate method that the *WithParams operations call before sending
+// anything, so a misplaced uri is reported as a *ValidationError instead of
+// reaching balanced.
+
+// Returns the kind of resource a uri points at, i.e. "accounts" for
+// /v1/marketplaces/MP123/accounts/AC123.
+func uriKind(uri string) string {
+	path, _, _ := strings.Cut(uri, "?")
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) < 2 {
+		return ""
+	}
+
+	return segments[len(segments)-2]
+}
+
+// Checks that uri, if given, points at one of the given kinds of resource.
+func checkUri(v *ValidationError, field, uri string, kinds ...string) {
+	if len(uri) == 0 {
+		return
+	}
+
+	if !strings.HasPrefix(uri, "/") {
+		v.add(field, "must be a uri, got %q", uri)
+		return
+	}
+
+	kind := uriKind(uri)
+	for _, k := range kinds {
+		if kind == k {
+			return
+		}
+	}
+
+	v.add(field, "must be a uri of %v, got %q", strings.Join(kinds, " or "), uri)
+}
+
+// Checks that amount is a positive number of cents. A zero amount is allowed
+// when optional.
+func checkAmount(v *ValidationError, amount int, optional bool) {
+	if amount < 0 || (amount == 0 && !optional) {
+		v.add("amount", "must be a positive amount in cents, got %v", amount)
+	}
+}
+
+// Checks that a soft descriptor fits on a statement.
+func checkAppearsOnStatementAs(v *ValidationError, appearsOnStatementAs string) {
+	if len(appearsOnStatementAs) > maxAppearsOnStatementAs {
+		v.add("appears_on_statement_as", "must be at most %v characters, got %q",
+			maxAppearsOnStatementAs, appearsOnStatementAs)
+	}
+}
+
+func addAmountToPayload(payload url.Values, amount int) {
+	if amount != 0 {
+		payload.Set("amount", strconv.Itoa(amount))
+	}
+}
+
+func addMetaToPayload(payload url.Values, meta MetaType) {
+	for key, value := range meta {
+		addToPayload(payload, "meta["+key+"]", value)
+	}
+}
@@ -0,0 +1,130 @@
+package balanced
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestParams(t *testing.T) {
+	card, err := TokenizeCardWithParams(&CardParams{
+		CardNumber:      testVisaCard,
+		ExpirationMonth: 12,
+		ExpirationYear:  testExpirationYear,
+		SecurityCode:    testVisaSecCode,
+		Name:            "Peter Sherman",
+		CountryCode:     "USA",
+	})
+	if err != nil {
+		t.Fatalf("Failed to tokenize card: %v", err)
+	}
+
+	account, err := CreateAccount()
+	if err != nil {
+		t.Fatalf("Unable to create account: %v", err)
+	}
+
+	hold, err := CreateNewHoldWithParams(account.HoldsUri, &HoldParams{
+		Amount:    700,
+		SourceUri: card.Uri,
+		Meta:      MetaType{"order_id": "7"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create hold: %v", err)
+	}
+
+	if hold.Amount != 700 || hold.Source.Uri() != card.Uri ||
+		hold.Meta["order_id"] != "7" {
+		t.Fatalf("Invalid hold created: %v", hold)
+	}
+
+	debit, err := CreateNewDebitWithParams(account.DebitsUri, &DebitParams{
+		HoldUri:     hold.Uri,
+		Description: "Order #7",
+	})
+	if err != nil {
+		t.Fatalf("Failed to capture hold: %v", err)
+	}
+
+	if debit.Amount != 700 || debit.Hold.Uri != hold.Uri {
+		t.Fatalf("Invalid debit created: %v", debit)
+	}
+
+	refund, err := IssueRefundWithParams(&RefundParams{
+		DebitUri: debit.Uri,
+		Amount:   200,
+	})
+	if err != nil {
+		t.Fatalf("Failed to refund debit: %v", err)
+	}
+
+	if refund.Amount != 200 || refund.Debit.Uri != debit.Uri {
+		t.Fatalf("Invalid refund created: %v", refund)
+	}
+
+	bankAccount, err := CreateNewBankAccount("Johann Bernoulli", "9900000001",
+		"121000358", BankAccountTypeChecking)
+	if err != nil {
+		t.Fatalf("Failed to create bank account: %v", err)
+	}
+
+	credit, err := CreateNewCreditWithParams(bankAccount.CreditsUri, &CreditParams{
+		Amount: 300,
+		Meta:   MetaType{"payout_id": "3"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create credit: %v", err)
+	}
+
+	if credit.Amount != 300 || credit.Meta["payout_id"] != "3" {
+		t.Fatalf("Invalid credit created: %v", credit)
+	}
+}
+
+func TestParamsValidation(t *testing.T) {
+	// Nothing reaches balanced when parameters are invalid
+	client := newStaticClient(http.StatusInternalServerError, "")
+
+	// Account and card uris swapped
+	_, err := client.CreateNewDebitWithParams("/v1/marketplaces/TEST-MP123/debits", &DebitParams{
+		Amount:     500,
+		AccountUri: "/v1/marketplaces/TEST-MP123/cards/CC1",
+		SourceUri:  "/v1/marketplaces/TEST-MP123/accounts/AC1",
+	})
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("Expected a validation error, got %v", err)
+	}
+
+	var validationError *ValidationError
+	if !errors.As(err, &validationError) || len(validationError.Errors) != 2 ||
+		validationError.Errors[0].Field != "account_uri" ||
+		validationError.Errors[1].Field != "source_uri" {
+		t.Fatalf("Invalid validation error: %v", err)
+	}
+
+	_, err = client.TokenizeCardWithParams(&CardParams{
+		CardNumber:      "4111 1111 1111 1111",
+		ExpirationMonth: 13,
+	})
+	if !errors.As(err, &validationError) || len(validationError.Errors) != 3 {
+		t.Fatalf("Expected 3 invalid fields, got %v", err)
+	}
+
+	_, err = client.CreateNewHoldWithParams("/v1/marketplaces/TEST-MP123/holds", nil)
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("Expected a validation error, got %v", err)
+	}
+
+	_, err = client.CreateNewCreditWithParams("/v1/bank_accounts/BA1/credits", &CreditParams{
+		Amount:               100,
+		AppearsOnStatementAs: "A descriptor that is far too long",
+	})
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("Expected a validation error, got %v", err)
+	}
+
+	_, err = client.IssueRefundWithParams(&RefundParams{Amount: -1})
+	if !errors.As(err, &validationError) || len(validationError.Errors) != 2 {
+		t.Fatalf("Expected 2 invalid fields, got %v", err)
+	}
+}
@@ -0,0 +1,184 @@
+package balanced
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Comparison operators for Query.Filter.
+const (
+	OpEqual          = ""
+	OpNotEqual       = "!="
+	OpLess           = "<"
+	OpLessOrEqual    = "<="
+	OpGreater        = ">"
+	OpGreaterOrEqual = ">="
+	// Matches any of a comma separated list of values.
+	OpIn = "in"
+)
+
+// Sort orders for Query.SortBy.
+const (
+	SortAscending  = "asc"
+	SortDescending = "desc"
+)
+
+const (
+	// Balanced's own default page size, so a Query without a limit lists the
+	// same page as a request without one. Iterators use the larger
+	// defaultPageSize instead, as they walk every page anyway and fewer
+	// requests are better.
+	defaultQueryLimit = 10
+)
+
+// A Query filters and sorts a list resource. It is encoded into the query
+// string of the request, so it works with any list, i.e. a marketplace's
+// debits or an account's transactions. Build one by chaining its methods:
+//
+//	query := balanced.NewQuery().
+//		CreatedBetween(monday, friday).
+//		Meta("order_id", "42").
+//		SortBy("amount", balanced.SortDescending)
+//	list, err := balanced.ListAllDebitsWithQuery(query)
+//
+// A nil *Query lists the first page in balanced's default order.
+type Query struct {
+	// Page to request. Limit defaults to 10. Iterators ignore both and walk
+	// every page.
+	Limit  int
+	Offset int
+
+	filters url.Values
+	sort    []string
+}
+
+// Creates a query for the first page of every item.
+func NewQuery() *Query {
+	return &Query{}
+}
+
+// Selects a page of results.
+func (q *Query) Page(limit, offset int) *Query {
+	q.Limit = limit
+	q.Offset = offset
+
+	return q
+}
+
+// Keeps items whose field compares to value with op, i.e.
+// Filter("amount", OpGreater, "1000"). Several filters on the same field and
+// operator must all match.
+func (q *Query) Filter(field, op, value string) *Query {
+	if q.filters == nil {
+		q.filters = url.Values{}
+	}
+
+	key := field
+	if op != OpEqual {
+		key += "[" + op + "]"
+	}
+	q.filters.Add(key, value)
+
+	return q
+}
+
+// Keeps items whose field equals value.
+func (q *Query) Where(field, value string) *Query {
+	return q.Filter(field, OpEqual, value)
+}
+
+// Keeps items whose field is any of values.
+func (q *Query) WhereIn(field string, values ...string) *Query {
+	return q.Filter(field, OpIn, strings.Join(values, ","))
+}
+
+// Keeps items with the given status, i.e. "succeeded".
+func (q *Query) Status(status string) *Query {
+	return q.Where("status", status)
+}
+
+// Keeps items whose meta has key set to value.
+func (q *Query) Meta(key, value string) *Query {
+	return q.Where("meta["+key+"]", value)
+}
+
+// Keeps items created at or after t.
+func (q *Query) CreatedAfter(t time.Time) *Query {
+	return q.Filter("created_at", OpGreaterOrEqual, formatQueryTime(t))
+}
+
+// Keeps items created before t.
+func (q *Query) CreatedBefore(t time.Time) *Query {
+	return q.Filter("created_at", OpLess, formatQueryTime(t))
+}
+
+// Keeps items created at or after from and before to.
+func (q *Query) CreatedBetween(from, to time.Time) *Query {
+	return q.CreatedAfter(from).CreatedBefore(to)
+}
+
+// Keeps items of at least min cents.
+func (q *Query) AmountAtLeast(min int) *Query {
+	return q.Filter("amount", OpGreaterOrEqual, strconv.Itoa(min))
+}
+
+// Keeps items of at most max cents.
+func (q *Query) AmountAtMost(max int) *Query {
+	return q.Filter("amount", OpLessOrEqual, strconv.Itoa(max))
+}
+
+// Keeps items of min to max cents, inclusive.
+func (q *Query) AmountBetween(min, max int) *Query {
+	return q.AmountAtLeast(min).AmountAtMost(max)
+}
+
+// Sorts items by field in the given order, SortAscending or SortDescending.
+// Later calls break ties of earlier ones.
+func (q *Query) SortBy(field, order string) *Query {
+	q.sort = append(q.sort, field+","+order)
+
+	return q
+}
+
+// Encodes the filters and sort order, without the page.
+func (q *Query) filterValues() url.Values {
+	values := url.Values{}
+	if q == nil {
+		return values
+	}
+
+	for key, filters := range q.filters {
+		values[key] = append([]string{}, filters...)
+	}
+	if len(q.sort) != 0 {
+		values["sort"] = append([]string{}, q.sort...)
+	}
+
+	return values
+}
+
+// Encodes the query into a request payload.
+func (q *Query) payload() url.Values {
+	if q == nil {
+		return defaultPayload(defaultQueryLimit, 0)
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultQueryLimit
+	}
+
+	payload := q.filterValues()
+	for key, values := range defaultPayload(limit, q.Offset) {
+		payload[key] = values
+	}
+
+	return payload
+}
+
+// Formats a time the way balanced expects it in filters.
+func formatQueryTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,106 @@
+package balanced
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestQueryPayload(t *testing.T) {
+	from := time.Date(2030, 1, 6, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 5)
+
+	query := NewQuery().
+		CreatedBetween(from, to).
+		Meta("order_id", "42").
+		WhereIn("status", "pending", "succeeded").
+		AmountAtLeast(500).
+		SortBy("amount", SortDescending).
+		SortBy("created_at", SortAscending).
+		Page(5, 10)
+
+	expected := url.Values{
+		"created_at[>=]": {"2030-01-06T00:00:00Z"},
+		"created_at[<]":  {"2030-01-11T00:00:00Z"},
+		"meta[order_id]": {"42"},
+		"status[in]":     {"pending,succeeded"},
+		"amount[>=]":     {"500"},
+		"sort":           {"amount,desc", "created_at,asc"},
+		"limit":          {"5"},
+		"offset":         {"10"},
+	}
+	if payload := query.payload(); !reflect.DeepEqual(payload, expected) {
+		t.Fatalf("Expected payload %v, got %v", expected, payload)
+	}
+
+	// A nil query is the first page
+	var empty *Query
+	if payload := empty.payload(); payload.Encode() != "limit=10&offset=0" {
+		t.Fatalf("Invalid payload for a nil query: %v", payload)
+	}
+}
+
+func TestListWithQuery(t *testing.T) {
+	account := createAccountWithCard(t, testVisaCard)
+	start := time.Now().Add(-time.Second)
+
+	amounts := []int{1500, 700, 3000, 2000}
+	for i, amount := range amounts {
+		orderId := "A"
+		if i%2 == 1 {
+			orderId = "B"
+		}
+
+		_, err := CreateNewDebitWithParams(account.DebitsUri, &DebitParams{
+			Amount: amount,
+			Meta:   MetaType{"order_id": orderId},
+		})
+		if err != nil {
+			t.Fatalf("Failed to create debit: %v", err)
+		}
+	}
+
+	query := NewQuery().
+		CreatedBetween(start, time.Now().Add(time.Second)).
+		Meta("order_id", "A").
+		SortBy("amount", SortAscending)
+
+	list, err := List[Debit](context.Background(), DefaultClient,
+		account.DebitsUri, query)
+	if err != nil {
+		t.Fatalf("Failed to list debits: %v", err)
+	}
+
+	if len(list.Items) != 2 || list.Items[0].Amount != 1500 ||
+		list.Items[1].Amount != 3000 {
+		t.Fatalf("Invalid debits listed: %v", list.Items)
+	}
+
+	// Filters carry over from page to page
+	query = NewQuery().AmountBetween(1000, 2500).SortBy("amount", SortDescending)
+	got := []string{}
+	for debit, err := range IterateDebitsForAccount(context.Background(),
+		account.DebitsUri, &IterOptions{PageSize: 1, Query: query}) {
+		if err != nil {
+			t.Fatalf("Failed to iterate over debits: %v", err)
+		}
+		got = append(got, strconv.Itoa(debit.Amount))
+	}
+
+	if !reflect.DeepEqual(got, []string{"2000", "1500"}) {
+		t.Fatalf("Invalid debits iterated over: %v", got)
+	}
+
+	// Nothing was created after the debits
+	list, err = ListAllDebitsWithQuery(NewQuery().CreatedAfter(time.Now().Add(time.Minute)))
+	if err != nil {
+		t.Fatalf("Failed to list debits: %v", err)
+	}
+
+	if len(list.Items) != 0 {
+		t.Fatalf("Expected no debits, got %v", list.Items)
+	}
+}
@@ -1,7 +1,9 @@
 package balanced
 
 import (
+	"context"
 	"fmt"
+	"iter"
 	"net/url"
 	"strconv"
 	"time"
@@ -25,22 +27,24 @@ type Refund struct {
 	Uri                  string    `json:"uri,omitempty"`
 }
 
-type ListOfRefunds struct {
-	FirstUri    string   `json:"first_uri,omitempty"`
-	Items       []Refund `json:"items,omitempty"`
-	LastUri     string   `json:"last_uri,omitempty"`
-	Limit       int      `json:"limit,omitempty"`
-	NextUri     string   `json:"next_uri,omitempty"`
-	Offset      int      `json:"offset,omitempty"`
-	PreviousUri string   `json:"previous_uri,omitempty"`
-	Total       int      `json:"total,omitempty"`
-	Uri         string   `json:"uri,omitempty"`
+type ListOfRefunds = Page[Refund]
+
+// Returns the card or bank account the refund is sent back to, the source of
+// the refunded debit.
+func (r *Refund) Destination() *FundingInstrument {
+	return &r.Debit.Source
 }
 
 // Issues a refund from a debit. You can either refund the full amount of the
 // debit or you can issue a partial refund, where the amount is less than the
 // charged amount.
-func IssueRefund(description, debitUri string, amount int, meta MetaType) (refund *Refund, err error) {
+func (c *Client) IssueRefund(description, debitUri string, amount int, meta MetaType) (refund *Refund, err error) {
+	return c.IssueRefundContext(context.Background(), description, debitUri,
+		amount, meta)
+}
+
+// IssueRefundContext is like IssueRefund but carries the given context.
+func (c *Client) IssueRefundContext(ctx context.Context, description, debitUri string, amount int, meta MetaType) (refund *Refund, err error) {
 	payload := url.Values{}
 
 	addToPayload(payload, "amount", strconv.Itoa(amount))
@@ -51,51 +55,206 @@ func IssueRefund(description, debitUri string, amount int, meta MetaType) (refun
 		addToPayload(payload, "meta["+key+"]", value)
 	}
 
-	uri := fmt.Sprintf(refundsUri, marketplaceId)
+	uri := fmt.Sprintf(refundsUri, c.MarketplaceId)
+
+	refund = &Refund{}
+	err = c.post(ctx, uri, payload, refund)
+
+	return
+}
+
+// IssueRefund is a wrapper around DefaultClient.IssueRefund.
+func IssueRefund(description, debitUri string, amount int, meta MetaType) (refund *Refund, err error) {
+	return DefaultClient.IssueRefund(description, debitUri, amount, meta)
+}
+
+// IssueRefundContext is a wrapper around DefaultClient.IssueRefundContext.
+func IssueRefundContext(ctx context.Context, description, debitUri string, amount int, meta MetaType) (refund *Refund, err error) {
+	return DefaultClient.IssueRefundContext(ctx, description, debitUri, amount,
+		meta)
+}
+
+// Parameters for refunding a debit with IssueRefundWithParams.
+type RefundParams struct {
+	// Uri of the debit to refund. Required.
+	DebitUri string
+	// Amount in cents. Defaults to the amount of the debit that has not been
+	// refunded yet.
+	Amount      int
+	Description string
+	Meta        MetaType
+}
+
+// Checks the parameters without contacting balanced.
+func (p *RefundParams) Validate() error {
+	v := &ValidationError{}
+
+	if len(p.DebitUri) == 0 {
+		v.add("debit_uri", "is required")
+	}
+	checkUri(v, "debit_uri", p.DebitUri, "debits")
+	checkAmount(v, p.Amount, true)
+
+	return v.err()
+}
+
+func (p *RefundParams) payload() url.Values {
+	payload := url.Values{}
+
+	addAmountToPayload(payload, p.Amount)
+	addToPayload(payload, "description", p.Description)
+	addToPayload(payload, "debit_uri", p.DebitUri)
+	addMetaToPayload(payload, p.Meta)
+
+	return payload
+}
+
+// Refunds a debit from named parameters, which are validated before the
+// request is sent.
+func (c *Client) IssueRefundWithParams(params *RefundParams) (refund *Refund, err error) {
+	return c.IssueRefundWithParamsContext(context.Background(), params)
+}
+
+// IssueRefundWithParamsContext is like IssueRefundWithParams but carries the given context.
+func (c *Client) IssueRefundWithParamsContext(ctx context.Context, params *RefundParams) (refund *Refund, err error) {
+	if params == nil {
+		params = &RefundParams{}
+	}
+
+	if err = params.Validate(); err != nil {
+		return nil, err
+	}
+
+	uri := fmt.Sprintf(refundsUri, c.MarketplaceId)
 
 	refund = &Refund{}
-	err = post(uri, payload, refund)
+	err = c.post(ctx, uri, params.payload(), refund)
 
 	return
 }
 
+// IssueRefundWithParams is a wrapper around DefaultClient.IssueRefundWithParams.
+func IssueRefundWithParams(params *RefundParams) (refund *Refund, err error) {
+	return DefaultClient.IssueRefundWithParams(params)
+}
+
+// IssueRefundWithParamsContext is a wrapper around DefaultClient.IssueRefundWithParamsContext.
+func IssueRefundWithParamsContext(ctx context.Context, params *RefundParams) (refund *Refund, err error) {
+	return DefaultClient.IssueRefundWithParamsContext(ctx, params)
+}
+
 // Retrieves the details of a refund that you've previously created. Use the uri
 // that was previously returned, and the corresponding refund information will
 // be returned.
-func RetrieveRefund(uri string) (refund *Refund, err error) {
+func (c *Client) RetrieveRefund(uri string) (refund *Refund, err error) {
+	return c.RetrieveRefundContext(context.Background(), uri)
+}
+
+// RetrieveRefundContext is like RetrieveRefund but carries the given context.
+func (c *Client) RetrieveRefundContext(ctx context.Context, uri string) (refund *Refund, err error) {
 	refund = &Refund{}
-	err = post(uri, nil, refund)
+	err = c.post(ctx, uri, nil, refund)
 
 	return
 }
 
+// RetrieveRefund is a wrapper around DefaultClient.RetrieveRefund.
+func RetrieveRefund(uri string) (refund *Refund, err error) {
+	return DefaultClient.RetrieveRefund(uri)
+}
+
+// RetrieveRefundContext is a wrapper around DefaultClient.RetrieveRefundContext.
+func RetrieveRefundContext(ctx context.Context, uri string) (refund *Refund, err error) {
+	return DefaultClient.RetrieveRefundContext(ctx, uri)
+}
+
 // Returns a list of refunds you've previously created. The refunds are returned
 // in sorted order, with the most recent refunds appearing first.
-func ListAllRefunds(limit, offset int) (listOfRefunds *ListOfRefunds, err error) {
+func (c *Client) ListAllRefunds(limit, offset int) (listOfRefunds *ListOfRefunds, err error) {
+	return c.ListAllRefundsContext(context.Background(), limit, offset)
+}
+
+// ListAllRefundsContext is like ListAllRefunds but carries the given context.
+func (c *Client) ListAllRefundsContext(ctx context.Context, limit, offset int) (listOfRefunds *ListOfRefunds, err error) {
 	payload := defaultPayload(limit, offset)
 
-	uri := fmt.Sprintf(refundsUri, marketplaceId)
+	uri := fmt.Sprintf(refundsUri, c.MarketplaceId)
 
 	listOfRefunds = &ListOfRefunds{}
-	err = get(uri, payload, listOfRefunds)
+	err = c.get(ctx, uri, payload, listOfRefunds)
 
 	return
 }
 
+// ListAllRefunds is a wrapper around DefaultClient.ListAllRefunds.
+func ListAllRefunds(limit, offset int) (listOfRefunds *ListOfRefunds, err error) {
+	return DefaultClient.ListAllRefunds(limit, offset)
+}
+
+// ListAllRefundsContext is a wrapper around DefaultClient.ListAllRefundsContext.
+func ListAllRefundsContext(ctx context.Context, limit, offset int) (listOfRefunds *ListOfRefunds, err error) {
+	return DefaultClient.ListAllRefundsContext(ctx, limit, offset)
+}
+
+// Returns the refunds selected by query, i.e. refunds created in a given
+// period or with a given meta value. See Query.
+func (c *Client) ListAllRefundsWithQuery(query *Query) (listOfRefunds *ListOfRefunds, err error) {
+	return c.ListAllRefundsWithQueryContext(context.Background(), query)
+}
+
+// ListAllRefundsWithQueryContext is like ListAllRefundsWithQuery but carries the given context.
+func (c *Client) ListAllRefundsWithQueryContext(ctx context.Context, query *Query) (listOfRefunds *ListOfRefunds, err error) {
+	uri := fmt.Sprintf(refundsUri, c.MarketplaceId)
+
+	return List[Refund](ctx, c, uri, query)
+}
+
+// ListAllRefundsWithQuery is a wrapper around DefaultClient.ListAllRefundsWithQuery.
+func ListAllRefundsWithQuery(query *Query) (listOfRefunds *ListOfRefunds, err error) {
+	return DefaultClient.ListAllRefundsWithQuery(query)
+}
+
+// ListAllRefundsWithQueryContext is a wrapper around DefaultClient.ListAllRefundsWithQueryContext.
+func ListAllRefundsWithQueryContext(ctx context.Context, query *Query) (listOfRefunds *ListOfRefunds, err error) {
+	return DefaultClient.ListAllRefundsWithQueryContext(ctx, query)
+}
+
 // Returns a list of refunds you've previously created against a specific
 // account. The refunds are returned in sorted order, with the most recent
 // refunds appearing first.
-func ListAllRefundsForAccount(uri string, limit, offset int) (listOfRefunds *ListOfRefunds, err error) {
+func (c *Client) ListAllRefundsForAccount(uri string, limit, offset int) (listOfRefunds *ListOfRefunds, err error) {
+	return c.ListAllRefundsForAccountContext(context.Background(), uri, limit,
+		offset)
+}
+
+// ListAllRefundsForAccountContext is like ListAllRefundsForAccount but carries the given context.
+func (c *Client) ListAllRefundsForAccountContext(ctx context.Context, uri string, limit, offset int) (listOfRefunds *ListOfRefunds, err error) {
 	payload := defaultPayload(limit, offset)
 
 	listOfRefunds = &ListOfRefunds{}
-	err = get(uri, payload, listOfRefunds)
+	err = c.get(ctx, uri, payload, listOfRefunds)
 
 	return
 }
 
+// ListAllRefundsForAccount is a wrapper around DefaultClient.ListAllRefundsForAccount.
+func ListAllRefundsForAccount(uri string, limit, offset int) (listOfRefunds *ListOfRefunds, err error) {
+	return DefaultClient.ListAllRefundsForAccount(uri, limit, offset)
+}
+
+// ListAllRefundsForAccountContext is a wrapper around DefaultClient.ListAllRefundsForAccountContext.
+func ListAllRefundsForAccountContext(ctx context.Context, uri string, limit, offset int) (listOfRefunds *ListOfRefunds, err error) {
+	return DefaultClient.ListAllRefundsForAccountContext(ctx, uri, limit,
+		offset)
+}
+
 // Updates information about a refund
-func UpdateRefund(uri, description string, meta MetaType) (refund *Refund, err error) {
+func (c *Client) UpdateRefund(uri, description string, meta MetaType) (refund *Refund, err error) {
+	return c.UpdateRefundContext(context.Background(), uri, description, meta)
+}
+
+// UpdateRefundContext is like UpdateRefund but carries the given context.
+func (c *Client) UpdateRefundContext(ctx context.Context, uri, description string, meta MetaType) (refund *Refund, err error) {
 	payload := url.Values{}
 
 	addToPayload(payload, "description", description)
@@ -105,7 +264,40 @@ func UpdateRefund(uri, description string, meta MetaType) (refund *Refund, err e
 	}
 
 	refund = &Refund{}
-	err = put(uri, payload, refund)
+	err = c.put(ctx, uri, payload, refund)
 
 	return
 }
+
+// UpdateRefund is a wrapper around DefaultClient.UpdateRefund.
+func UpdateRefund(uri, description string, meta MetaType) (refund *Refund, err error) {
+	return DefaultClient.UpdateRefund(uri, description, meta)
+}
+
+// UpdateRefundContext is a wrapper around DefaultClient.UpdateRefundContext.
+func UpdateRefundContext(ctx context.Context, uri, description string, meta MetaType) (refund *Refund, err error) {
+	return DefaultClient.UpdateRefundContext(ctx, uri, description, meta)
+}
+
+// Iterates over every refund you've previously created, most recent first.
+func (c *Client) IterateRefunds(ctx context.Context, options *IterOptions) iter.Seq2[Refund, error] {
+	uri := fmt.Sprintf(refundsUri, c.MarketplaceId)
+
+	return Iterate[Refund](ctx, c, uri, options)
+}
+
+// IterateRefunds is a wrapper around DefaultClient.IterateRefunds.
+func IterateRefunds(ctx context.Context, options *IterOptions) iter.Seq2[Refund, error] {
+	return DefaultClient.IterateRefunds(ctx, options)
+}
+
+// Iterates over every refund created against the account whose refunds_uri
+// is given, most recent first.
+func (c *Client) IterateRefundsForAccount(ctx context.Context, uri string, options *IterOptions) iter.Seq2[Refund, error] {
+	return Iterate[Refund](ctx, c, uri, options)
+}
+
+// IterateRefundsForAccount is a wrapper around DefaultClient.IterateRefundsForAccount.
+func IterateRefundsForAccount(ctx context.Context, uri string, options *IterOptions) iter.Seq2[Refund, error] {
+	return DefaultClient.IterateRefundsForAccount(ctx, uri, options)
+}
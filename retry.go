@@ -0,0 +1,169 @@
+package balanced
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	mathrand "math/rand/v2"
+	"net/url"
+	"time"
+)
+
+const (
+	// Header carrying the idempotency key of a POST or PUT request.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// Meta key the idempotency key is recorded under.
+	IdempotencyKeyMeta = "idempotency_key"
+)
+
+// Controls how failed requests are retried. Only requests that are safe to
+// repeat are retried: GET requests, and POST or PUT requests whose context
+// carries an idempotency key (see WithIdempotencyKey). A request is retried
+// when it fails to reach balanced or balanced responds with a 5xx status.
+type RetryPolicy struct {
+	// Maximum number of attempts, including the first one.
+	MaxAttempts int
+	// Wait before the first retry.
+	InitialBackoff time.Duration
+	// Upper bound for the wait between retries.
+	MaxBackoff time.Duration
+	// Factor the wait grows by after every retry.
+	Multiplier float64
+}
+
+// A reasonable retry policy for most applications.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    4,
+	InitialBackoff: 250 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	Multiplier:     2,
+}
+
+// Returned by clients with a RetryPolicy when a request fails. Attempts is the
+// number of times the request was sent and Err is the error of the last
+// attempt.
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("%v (after %v attempts)", e.Err, e.Attempts)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+type idempotencyKeyContextKey struct{}
+
+// Attaches an idempotency key to the context. POST and PUT requests made with
+// the returned context send the key in the Idempotency-Key header, record it
+// in meta[idempotency_key] and become eligible for retries. Use a new key for
+// every logical operation, i.e. one per debit, and reuse it when repeating
+// that operation.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// Returns the idempotency key attached to the context, if any.
+func IdempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyContextKey{}).(string)
+
+	return key, ok && len(key) != 0
+}
+
+// Generates a random idempotency key.
+func NewIdempotencyKey() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	return hex.EncodeToString(b)
+}
+
+// Adds the idempotency key in the context, if any, to a mutating request.
+func addIdempotencyKey(ctx context.Context, req *Request) {
+	key, ok := IdempotencyKeyFromContext(ctx)
+	if !ok || (req.Method != "POST" && req.Method != "PUT") {
+		return
+	}
+
+	// Copy the payload so the caller's values are left untouched
+	payload := url.Values{}
+	for k, v := range req.Payload {
+		payload[k] = v
+	}
+	payload.Set("meta["+IdempotencyKeyMeta+"]", key)
+
+	req.Payload = payload
+	req.Header.Set(IdempotencyKeyHeader, key)
+}
+
+// Reports whether a request can safely be sent more than once.
+func isRepeatable(req *Request) bool {
+	switch req.Method {
+	case "GET":
+		return true
+	case "POST", "PUT":
+		return len(req.Header.Get(IdempotencyKeyHeader)) != 0
+	}
+
+	return false
+}
+
+// Reports whether a failed attempt is worth retrying.
+func isTransient(resp *Response, err error) bool {
+	if resp != nil {
+		return resp.StatusCode >= 500
+	}
+
+	return errors.Is(err, ErrNetwork)
+}
+
+// Reports whether an operation that failed with err is worth trying again
+// later, by the same rules isTransient applies to single requests.
+func isTransientError(err error) bool {
+	var apiErr *ApiError
+	if errors.As(err, &apiErr) {
+		return isTransient(&Response{StatusCode: apiErr.StatusCode}, nil)
+	}
+
+	return isTransient(nil, err)
+}
+
+// Returns how long to wait before the given retry, starting at 1.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	wait := float64(p.InitialBackoff) * math.Pow(multiplier, float64(retry-1))
+	if p.MaxBackoff > 0 && wait > float64(p.MaxBackoff) {
+		wait = float64(p.MaxBackoff)
+	}
+
+	// Randomize between half and the full wait, so clients that failed
+	// together do not retry together.
+	half := wait / 2
+
+	return time.Duration(half + mathrand.Float64()*half)
+}
+
+// Waits for the given duration, or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,106 @@
+package balanced
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// Creates a client whose requests are answered by the given statuses in turn,
+// recording every request it sees.
+func newFlakyClient(statuses []int, seen *[]*Request) *Client {
+	client := NewClient("http://balanced.invalid", "ak-test-key", "TEST-MP123")
+	client.RetryPolicy = &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		Multiplier:     2,
+	}
+	client.Use(func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			status := statuses[len(*seen)]
+			*seen = append(*seen, req)
+
+			if status != http.StatusOK {
+				return &Response{
+					StatusCode: status,
+					Body: []byte(`{"status": "Service Unavailable",
+						"status_code": 503}`),
+				}, nil
+			}
+
+			return &Response{
+				StatusCode: status,
+				Body:       []byte(`{"uri": "/v1/marketplaces/TEST-MP123/debits/WD1"}`),
+			}, nil
+		}
+	})
+
+	return client
+}
+
+func TestRetryGet(t *testing.T) {
+	var seen []*Request
+	client := newFlakyClient([]int{503, 503, 200}, &seen)
+
+	debit, err := client.RetrieveDebit("/v1/marketplaces/TEST-MP123/debits/WD1")
+	if err != nil {
+		t.Fatalf("Failed to retrieve debit: %v", err)
+	}
+
+	if len(seen) != 3 || len(debit.Uri) == 0 {
+		t.Fatalf("Expected 3 attempts, got %v", len(seen))
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	var seen []*Request
+	client := newFlakyClient([]int{503, 503, 503}, &seen)
+
+	_, err := client.RetrieveDebit("/v1/marketplaces/TEST-MP123/debits/WD1")
+
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) || retryErr.Attempts != 3 {
+		t.Fatalf("Expected a retry error after 3 attempts, got %v", err)
+	}
+}
+
+func TestRetryPostWithoutIdempotencyKey(t *testing.T) {
+	var seen []*Request
+	client := newFlakyClient([]int{503, 200}, &seen)
+
+	_, err := client.CreateNewDebit("/v1/marketplaces/TEST-MP123/debits", "",
+		"", "", "", "", "", 100, nil)
+
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) || retryErr.Attempts != 1 || len(seen) != 1 {
+		t.Fatalf("Expected a single attempt, got %v", err)
+	}
+}
+
+func TestRetryPostWithIdempotencyKey(t *testing.T) {
+	var seen []*Request
+	client := newFlakyClient([]int{503, 200}, &seen)
+
+	key := NewIdempotencyKey()
+	ctx := WithIdempotencyKey(context.Background(), key)
+
+	_, err := client.CreateNewDebitContext(ctx, "/v1/marketplaces/TEST-MP123/debits",
+		"", "", "", "", "", "", 100, nil)
+	if err != nil {
+		t.Fatalf("Failed to create debit: %v", err)
+	}
+
+	if len(seen) != 2 {
+		t.Fatalf("Expected 2 attempts, got %v", len(seen))
+	}
+
+	for _, req := range seen {
+		if req.Header.Get(IdempotencyKeyHeader) != key ||
+			req.Payload.Get("meta["+IdempotencyKeyMeta+"]") != key {
+			t.Fatalf("Idempotency key missing from request: %v", req)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package balanced
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSettleInitialInterval = 30 * time.Second
+	defaultSettleMaxInterval     = 30 * time.Minute
+	defaultSettleMultiplier      = 2
+	defaultSettleConcurrency     = 8
+)
+
+// Controls how WaitForCredit, WaitForDebit and WatchSettlements poll. ACH
+// transfers settle the next business day, so the wait between refreshes grows
+// from InitialInterval up to MaxInterval.
+type SettleOptions struct {
+	// Wait before the first refresh. Defaults to 30 seconds.
+	InitialInterval time.Duration
+	// Upper bound for the wait between refreshes. Defaults to 30 minutes.
+	MaxInterval time.Duration
+	// Factor the wait grows by after every refresh. Defaults to 2.
+	Multiplier float64
+	// Time after which waiting stops with ErrNotSettled. Zero waits until
+	// the context is done.
+	MaxDuration time.Duration
+	// Number of transfers WatchSettlements refreshes at once. Defaults to 8.
+	Concurrency int
+}
+
+func (o *SettleOptions) withDefaults() SettleOptions {
+	options := SettleOptions{}
+	if o != nil {
+		options = *o
+	}
+
+	if options.InitialInterval <= 0 {
+		options.InitialInterval = defaultSettleInitialInterval
+	}
+	if options.MaxInterval <= 0 {
+		options.MaxInterval = defaultSettleMaxInterval
+	}
+	if options.Multiplier < 1 {
+		options.Multiplier = defaultSettleMultiplier
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = defaultSettleConcurrency
+	}
+
+	return options
+}
+
+// Refreshes a resource with retrieve until settled reports true, waiting
+// longer after every refresh. Transient errors are retried like pending
+// resources. Returns the last resource retrieved successfully, if any, along
+// with ErrNotSettled once MaxDuration is over, ctx.Err() once ctx is done, or
+// the first error that is not transient.
+func waitForSettlement[T any](ctx context.Context, options *SettleOptions, retrieve func(ctx context.Context) (T, error), settled func(T) bool) (last T, err error) {
+	o := options.withDefaults()
+	policy := &RetryPolicy{
+		InitialBackoff: o.InitialInterval,
+		MaxBackoff:     o.MaxInterval,
+		Multiplier:     o.Multiplier,
+	}
+
+	var deadline time.Time
+	if o.MaxDuration > 0 {
+		deadline = time.Now().Add(o.MaxDuration)
+	}
+
+	for refresh := 1; ; refresh++ {
+		resource, err := retrieve(ctx)
+		switch {
+		case ctx.Err() != nil:
+			return last, ctx.Err()
+		case err != nil && !isTransientError(err):
+			return last, err
+		case err == nil:
+			last = resource
+			if settled(resource) {
+				return resource, nil
+			}
+		}
+
+		wait := policy.backoff(refresh)
+		if !deadline.IsZero() {
+			// Refresh one last time once MaxDuration is over
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				if err != nil {
+					return last, fmt.Errorf("%w: %w", ErrNotSettled, err)
+				}
+				return last, ErrNotSettled
+			}
+			wait = min(wait, remaining)
+		}
+
+		if err := sleep(ctx, wait); err != nil {
+			return last, err
+		}
+	}
+}
+
+// Waits until the credit at uri is paid or failed, and returns it. Network
+// errors and 5xx responses are retried. Returns the last credit retrieved
+// along with ErrNotSettled once options.MaxDuration is over, ctx.Err() once
+// ctx is done, or any other error a refresh fails with. The credit is nil when
+// it could not be retrieved at all. options may be nil.
+func (c *Client) WaitForCredit(ctx context.Context, uri string, options *SettleOptions) (*Credit, error) {
+	return waitForSettlement(ctx, options,
+		func(ctx context.Context) (*Credit, error) {
+			return c.RetrieveCreditContext(ctx, uri)
+		},
+		func(credit *Credit) bool {
+			return credit.Status.IsFinal()
+		})
+}
+
+// WaitForCredit is a wrapper around DefaultClient.WaitForCredit.
+func WaitForCredit(ctx context.Context, uri string, options *SettleOptions) (*Credit, error) {
+	return DefaultClient.WaitForCredit(ctx, uri, options)
+}
+
+// Waits until the debit at uri succeeded or failed, and returns it, retrying
+// errors like WaitForCredit. Returns the last debit retrieved along with
+// ErrNotSettled once options.MaxDuration is over, ctx.Err() once ctx is done,
+// or any other error a refresh fails with. The debit is nil when it could not
+// be retrieved at all. options may be nil.
+func (c *Client) WaitForDebit(ctx context.Context, uri string, options *SettleOptions) (*Debit, error) {
+	return waitForSettlement(ctx, options,
+		func(ctx context.Context) (*Debit, error) {
+			return c.RetrieveDebitContext(ctx, uri)
+		},
+		func(debit *Debit) bool {
+			return debit.Status.IsFinal()
+		})
+}
+
+// WaitForDebit is a wrapper around DefaultClient.WaitForDebit.
+func WaitForDebit(ctx context.Context, uri string, options *SettleOptions) (*Debit, error) {
+	return DefaultClient.WaitForDebit(ctx, uri, options)
+}
+
+// The outcome of waiting for a debit or credit to settle. Debit or Credit is
+// the last one retrieved, and both are nil when the transfer could not be
+// retrieved at all. Err is set when the transfer did not settle, i.e.
+// ErrNotSettled, ctx.Err() or an error that is not worth retrying.
+type Settlement struct {
+	Uri    string
+	Debit  *Debit
+	Credit *Credit
+	Err    error
+}
+
+// Reports whether the transfer settled and the funds moved.
+func (s *Settlement) IsSuccessful() bool {
+	switch {
+	case s.Err != nil:
+		return false
+	case s.Debit != nil:
+		return s.Debit.Status.IsSuccessful()
+	case s.Credit != nil:
+		return s.Credit.Status.IsSuccessful()
+	}
+
+	return false
+}
+
+// Waits for each debit or credit at uris to settle, as WaitForDebit and
+// WaitForCredit do, refreshing up to options.Concurrency of them at once.
+// Settlements are sent to the returned channel as they happen, in no
+// particular order, and the channel is closed once every uri was reported.
+// Cancelling ctx stops the watch and closes the channel, dropping the uris
+// that were not reported yet, so a caller may stop receiving after cancelling.
+//
+//	for settlement := range client.WatchSettlements(ctx, uris, nil) {
+//		if !settlement.IsSuccessful() {
+//			...
+//		}
+//	}
+func (c *Client) WatchSettlements(ctx context.Context, uris []string, options *SettleOptions) <-chan Settlement {
+	settlements := make(chan Settlement)
+	slots := make(chan struct{}, options.withDefaults().Concurrency)
+
+	// Each refresh takes a slot, but waiting in between does not
+	limited := func(ctx context.Context, retrieve func() error) error {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		defer func() { <-slots }()
+
+		return retrieve()
+	}
+
+	var wg sync.WaitGroup
+	for _, uri := range uris {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			select {
+			case settlements <- c.watchSettlement(ctx, uri, options, limited):
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(settlements)
+	}()
+
+	return settlements
+}
+
+// WatchSettlements is a wrapper around DefaultClient.WatchSettlements.
+func WatchSettlements(ctx context.Context, uris []string, options *SettleOptions) <-chan Settlement {
+	return DefaultClient.WatchSettlements(ctx, uris, options)
+}
+
+func (c *Client) watchSettlement(ctx context.Context, uri string, options *SettleOptions, limited func(ctx context.Context, retrieve func() error) error) Settlement {
+	settlement := Settlement{Uri: uri}
+
+	switch uriKind(uri) {
+	case "debits":
+		settlement.Debit, settlement.Err = waitForSettlement(ctx, options,
+			func(ctx context.Context) (debit *Debit, err error) {
+				err = limited(ctx, func() error {
+					debit, err = c.RetrieveDebitContext(ctx, uri)
+					return err
+				})
+				return debit, err
+			},
+			func(debit *Debit) bool {
+				return debit.Status.IsFinal()
+			})
+
+	case "credits":
+		settlement.Credit, settlement.Err = waitForSettlement(ctx, options,
+			func(ctx context.Context) (credit *Credit, err error) {
+				err = limited(ctx, func() error {
+					credit, err = c.RetrieveCreditContext(ctx, uri)
+					return err
+				})
+				return credit, err
+			},
+			func(credit *Credit) bool {
+				return credit.Status.IsFinal()
+			})
+
+	default:
+		settlement.Err = fmt.Errorf("Balanced API: %v is not a debit or credit uri", uri)
+	}
+
+	return settlement
+}
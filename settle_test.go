@@ -0,0 +1,205 @@
+package balanced
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nimajalali/balanced-go/balancedtest"
+)
+
+// Creates a client for a fresh fake server, so that transfers can be settled
+// on it.
+func newSettleTestClient(t *testing.T) (*Client, *balancedtest.Server) {
+	server := balancedtest.NewServer()
+	t.Cleanup(server.Close)
+
+	return NewClient(server.URL, server.ApiKey, server.MarketplaceId), server
+}
+
+func newPendingCredit(t *testing.T, client *Client) *Credit {
+	credit, err := client.CreditNewBankAccount(50, "Payout", &BankAccount{
+		Name:          "Johann Bernoulli",
+		AccountNumber: "9900000001",
+		RoutingNumber: "121000358",
+		Type:          BankAccountTypeChecking,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create credit: %v", err)
+	}
+
+	if credit.Status != CreditStatusPending {
+		t.Fatalf("Expected a pending credit, got %v", credit.Status)
+	}
+
+	return credit
+}
+
+func TestWaitForCredit(t *testing.T) {
+	client, server := newSettleTestClient(t)
+	credit := newPendingCredit(t, client)
+
+	time.AfterFunc(20*time.Millisecond, func() {
+		server.Settle(credit.Uri, "paid")
+	})
+
+	settled, err := client.WaitForCredit(context.Background(), credit.Uri,
+		&SettleOptions{InitialInterval: time.Millisecond, MaxInterval: 5 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Failed to wait for credit: %v", err)
+	}
+
+	if settled.Uri != credit.Uri || settled.Status != CreditStatusPaid {
+		t.Fatalf("Invalid credit settled: %v", settled)
+	}
+}
+
+func TestWaitForCreditNotSettled(t *testing.T) {
+	client, _ := newSettleTestClient(t)
+	credit := newPendingCredit(t, client)
+
+	settled, err := client.WaitForCredit(context.Background(), credit.Uri,
+		&SettleOptions{InitialInterval: time.Millisecond, MaxDuration: 20 * time.Millisecond})
+	if !errors.Is(err, ErrNotSettled) {
+		t.Fatalf("Expected the credit not to settle, got %v", err)
+	}
+
+	if settled == nil || settled.Status != CreditStatusPending {
+		t.Fatalf("Expected the pending credit, got %v", settled)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = client.WaitForCredit(ctx, credit.Uri, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the wait to be cancelled, got %v", err)
+	}
+}
+
+func TestWaitForDebit(t *testing.T) {
+	account := createAccountWithCard(t, testVisaCard)
+	debit := createNewDebit(t, account)
+
+	// Card debits settle right away
+	settled, err := WaitForDebit(context.Background(), debit.Uri, nil)
+	if err != nil {
+		t.Fatalf("Failed to wait for debit: %v", err)
+	}
+
+	if settled.Id != debit.Id || !settled.Status.IsSuccessful() {
+		t.Fatalf("Invalid debit settled: %v", settled)
+	}
+}
+
+func TestWatchSettlements(t *testing.T) {
+	client, server := newSettleTestClient(t)
+
+	paid := newPendingCredit(t, client)
+	failed := newPendingCredit(t, client)
+	pending := newPendingCredit(t, client)
+	invalid := "/v1/marketplaces/" + server.MarketplaceId + "/holds/HL1"
+
+	if err := server.Settle(failed.Uri, "failed"); err != nil {
+		t.Fatalf("Failed to settle credit: %v", err)
+	}
+	time.AfterFunc(20*time.Millisecond, func() {
+		server.Settle(paid.Uri, "succeeded")
+	})
+
+	settlements := client.WatchSettlements(context.Background(),
+		[]string{paid.Uri, failed.Uri, pending.Uri, invalid},
+		&SettleOptions{
+			InitialInterval: time.Millisecond,
+			MaxInterval:     5 * time.Millisecond,
+			MaxDuration:     200 * time.Millisecond,
+			Concurrency:     2,
+		})
+
+	order := []string{}
+	results := map[string]Settlement{}
+	for settlement := range settlements {
+		order = append(order, settlement.Uri)
+		results[settlement.Uri] = settlement
+	}
+
+	if len(results) != 4 {
+		t.Fatalf("Expected 4 settlements, got %v", order)
+	}
+
+	// Settlements are reported as they happen
+	if order[len(order)-1] != pending.Uri {
+		t.Fatalf("Expected the pending credit to be reported last, got %v", order)
+	}
+
+	if s := results[paid.Uri]; s.Err != nil || !s.IsSuccessful() ||
+		s.Credit.Status != CreditStatusSucceeded {
+		t.Fatalf("Invalid settlement for paid credit: %v", s)
+	}
+	if s := results[failed.Uri]; s.Err != nil || s.IsSuccessful() ||
+		s.Credit.Status != CreditStatusFailed {
+		t.Fatalf("Invalid settlement for failed credit: %v", s)
+	}
+	if s := results[pending.Uri]; !errors.Is(s.Err, ErrNotSettled) ||
+		s.IsSuccessful() || s.Credit == nil {
+		t.Fatalf("Invalid settlement for pending credit: %v", s)
+	}
+	if s := results[invalid]; s.Err == nil || s.Debit != nil || s.Credit != nil {
+		t.Fatalf("Invalid settlement for hold: %v", s)
+	}
+}
+
+func TestWaitForCreditRetriesTransientErrors(t *testing.T) {
+	client, server := newSettleTestClient(t)
+	credit := newPendingCredit(t, client)
+
+	// Balanced is unavailable for a few refreshes, then the credit settles
+	var failures atomic.Int32
+	client.Use(func(next Handler) Handler {
+		return func(ctx context.Context, req *Request) (*Response, error) {
+			switch failures.Add(1) {
+			case 1:
+				return nil, &networkError{Method: req.Method, Err: errors.New("connection reset")}
+			case 2, 3:
+				return &Response{StatusCode: http.StatusServiceUnavailable,
+					Body: []byte(`{}`)}, nil
+			case 4:
+				server.Settle(credit.Uri, "succeeded")
+			}
+
+			return next(ctx, req)
+		}
+	})
+
+	settled, err := client.WaitForCredit(context.Background(), credit.Uri,
+		&SettleOptions{InitialInterval: time.Millisecond, MaxInterval: 5 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Failed to wait for credit: %v", err)
+	}
+
+	if settled.Status != CreditStatusSucceeded || failures.Load() != 4 {
+		t.Fatalf("Invalid credit settled after %v refreshes: %v",
+			failures.Load(), settled)
+	}
+}
+
+func TestWatchSettlementsPermanentErrors(t *testing.T) {
+	client, _ := newSettleTestClient(t)
+	missing := "/v1/credits/CR0"
+
+	settlements := client.WatchSettlements(context.Background(),
+		[]string{missing}, &SettleOptions{InitialInterval: time.Millisecond})
+
+	settlement := <-settlements
+	if !errors.Is(settlement.Err, ErrNotFound) || settlement.Credit != nil ||
+		settlement.Debit != nil {
+		t.Fatalf("Invalid settlement for missing credit: %+v", settlement)
+	}
+
+	if _, ok := <-settlements; ok {
+		t.Fatal("Expected the settlements to be closed")
+	}
+}
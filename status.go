@@ -0,0 +1,214 @@
+package balanced
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Statuses of a debit. Card debits succeed or fail right away, bank account
+// debits are pending until the ACH transfer settles.
+type DebitStatus string
+
+const (
+	DebitStatusPending   DebitStatus = "pending"
+	DebitStatusSucceeded DebitStatus = "succeeded"
+	DebitStatusFailed    DebitStatus = "failed"
+)
+
+// Reports whether the debit will not change status anymore.
+func (s DebitStatus) IsFinal() bool {
+	return s == DebitStatusSucceeded || s == DebitStatusFailed
+}
+
+// Reports whether the funds were debited.
+func (s DebitStatus) IsSuccessful() bool {
+	return s == DebitStatusSucceeded
+}
+
+// Reports whether a debit can go from status s to next.
+func (s DebitStatus) CanTransitionTo(next DebitStatus) bool {
+	return canTransition(s == next, s == DebitStatusPending, next.IsFinal())
+}
+
+// Statuses of a credit. Credits are pending until the next day ACH transfer
+// settles. Older marketplaces report settled credits as paid, newer ones as
+// succeeded.
+type CreditStatus string
+
+const (
+	CreditStatusPending   CreditStatus = "pending"
+	CreditStatusPaid      CreditStatus = "paid"
+	CreditStatusSucceeded CreditStatus = "succeeded"
+	CreditStatusFailed    CreditStatus = "failed"
+)
+
+// Reports whether the credit will not change status anymore.
+func (s CreditStatus) IsFinal() bool {
+	return s == CreditStatusPaid || s == CreditStatusSucceeded ||
+		s == CreditStatusFailed
+}
+
+// Reports whether the funds were sent.
+func (s CreditStatus) IsSuccessful() bool {
+	return s == CreditStatusPaid || s == CreditStatusSucceeded
+}
+
+// Reports whether a credit can go from status s to next.
+func (s CreditStatus) CanTransitionTo(next CreditStatus) bool {
+	return canTransition(s == next, s == CreditStatusPending, next.IsFinal())
+}
+
+// States of a bank account verification. A verification stays pending until
+// the amounts of the two deposits are confirmed, or too many attempts fail.
+type VerificationState string
+
+const (
+	VerificationStatePending  VerificationState = "pending"
+	VerificationStateVerified VerificationState = "verified"
+	VerificationStateFailed   VerificationState = "failed"
+)
+
+// Reports whether the verification will not change state anymore.
+func (s VerificationState) IsFinal() bool {
+	return s == VerificationStateVerified || s == VerificationStateFailed
+}
+
+// Reports whether the bank account was verified.
+func (s VerificationState) IsSuccessful() bool {
+	return s == VerificationStateVerified
+}
+
+// Reports whether a verification can go from state s to next.
+func (s VerificationState) CanTransitionTo(next VerificationState) bool {
+	return canTransition(s == next, s == VerificationStatePending,
+		next.IsFinal())
+}
+
+// Statuses only ever move from pending to a final status.
+func canTransition(same, fromPending, toFinal bool) bool {
+	return same || (fromPending && toFinal)
+}
+
+// Returned when a resource was observed changing status in a way that is not
+// possible. Matches ErrInvalidTransition with errors.Is.
+type TransitionError struct {
+	// Type of the resource, i.e. "debit".
+	Type string
+	Uri  string
+	From string
+	To   string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("Balanced API: Invalid %v status transition from %v to %v for %v",
+		e.Type, e.From, e.To, e.Uri)
+}
+
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// A StatusTracker remembers the last status seen for each debit, credit and
+// verification, and flags impossible transitions, i.e. a debit going back from
+// succeeded to pending. Feed it resources as they are refreshed, or events as
+// they are received:
+//
+//	tracker := balanced.NewStatusTracker()
+//	receiver.HandleFunc(balanced.AnyEventType, func(ctx context.Context, event *balanced.Event) error {
+//		if err := tracker.ObserveEvent(event); err != nil {
+//			log.Println(err)
+//		}
+//		...
+//	})
+//
+// An impossible transition is reported as a *TransitionError and not recorded,
+// so a stale event delivered late does not overwrite a final status.
+type StatusTracker struct {
+	mu       sync.Mutex
+	statuses map[string]string
+}
+
+// Creates a tracker that has not seen any resource yet.
+func NewStatusTracker() *StatusTracker {
+	return &StatusTracker{}
+}
+
+// Returns the last status recorded for the resource at uri, or "" if it was
+// never seen.
+func (t *StatusTracker) Status(uri string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.statuses[uri]
+}
+
+// Stops tracking the resource at uri.
+func (t *StatusTracker) Forget(uri string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.statuses, uri)
+}
+
+// Records the status of a debit.
+func (t *StatusTracker) ObserveDebit(debit *Debit) error {
+	return t.observe("debit", debit.Uri, string(debit.Status),
+		func(from string) bool {
+			return DebitStatus(from).CanTransitionTo(debit.Status)
+		})
+}
+
+// Records the status of a credit.
+func (t *StatusTracker) ObserveCredit(credit *Credit) error {
+	return t.observe("credit", credit.Uri, string(credit.Status),
+		func(from string) bool {
+			return CreditStatus(from).CanTransitionTo(credit.Status)
+		})
+}
+
+// Records the state of a bank account verification.
+func (t *StatusTracker) ObserveVerification(verification *Verification) error {
+	return t.observe("verification", verification.Uri,
+		string(verification.State), func(from string) bool {
+			return VerificationState(from).CanTransitionTo(verification.State)
+		})
+}
+
+// Records the status of the debit or credit an event is about. Events about
+// other resources are ignored.
+func (t *StatusTracker) ObserveEvent(event *Event) error {
+	if debit := event.Entity.Debit(); debit != nil {
+		return t.ObserveDebit(debit)
+	}
+	if credit := event.Entity.Credit(); credit != nil {
+		return t.ObserveCredit(credit)
+	}
+
+	return nil
+}
+
+func (t *StatusTracker) observe(resourceType, uri, status string, allowed func(from string) bool) error {
+	if len(uri) == 0 || len(status) == 0 {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.statuses == nil {
+		t.statuses = map[string]string{}
+	}
+
+	if from, seen := t.statuses[uri]; seen && !allowed(from) {
+		return &TransitionError{
+			Type: resourceType,
+			Uri:  uri,
+			From: from,
+			To:   status,
+		}
+	}
+
+	t.statuses[uri] = status
+
+	return nil
+}
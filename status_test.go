@@ -0,0 +1,143 @@
+package balanced
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestStatusPredicates(t *testing.T) {
+	debits := map[DebitStatus][2]bool{
+		DebitStatusPending:   {false, false},
+		DebitStatusSucceeded: {true, true},
+		DebitStatusFailed:    {true, false},
+		"reversed":           {false, false},
+	}
+	for status, expected := range debits {
+		if status.IsFinal() != expected[0] || status.IsSuccessful() != expected[1] {
+			t.Errorf("Invalid predicates for debit status %v", status)
+		}
+	}
+
+	credits := map[CreditStatus][2]bool{
+		CreditStatusPending:   {false, false},
+		CreditStatusPaid:      {true, true},
+		CreditStatusSucceeded: {true, true},
+		CreditStatusFailed:    {true, false},
+	}
+	for status, expected := range credits {
+		if status.IsFinal() != expected[0] || status.IsSuccessful() != expected[1] {
+			t.Errorf("Invalid predicates for credit status %v", status)
+		}
+	}
+
+	verifications := map[VerificationState][2]bool{
+		VerificationStatePending:  {false, false},
+		VerificationStateVerified: {true, true},
+		VerificationStateFailed:   {true, false},
+	}
+	for state, expected := range verifications {
+		if state.IsFinal() != expected[0] || state.IsSuccessful() != expected[1] {
+			t.Errorf("Invalid predicates for verification state %v", state)
+		}
+	}
+}
+
+func TestStatusTransitions(t *testing.T) {
+	transitions := []struct {
+		from, to CreditStatus
+		allowed  bool
+	}{
+		{CreditStatusPending, CreditStatusPending, true},
+		{CreditStatusPending, CreditStatusPaid, true},
+		{CreditStatusPending, CreditStatusFailed, true},
+		{CreditStatusPaid, CreditStatusPaid, true},
+		{CreditStatusPaid, CreditStatusPending, false},
+		{CreditStatusSucceeded, CreditStatusFailed, false},
+		{CreditStatusFailed, CreditStatusSucceeded, false},
+		{CreditStatusPending, "reversed", false},
+	}
+	for _, transition := range transitions {
+		if transition.from.CanTransitionTo(transition.to) != transition.allowed {
+			t.Errorf("Expected transition from %v to %v allowed to be %v",
+				transition.from, transition.to, transition.allowed)
+		}
+	}
+
+	if !DebitStatusPending.CanTransitionTo(DebitStatusSucceeded) ||
+		DebitStatusSucceeded.CanTransitionTo(DebitStatusFailed) {
+		t.Error("Invalid debit transitions")
+	}
+	if !VerificationStatePending.CanTransitionTo(VerificationStateVerified) ||
+		VerificationStateFailed.CanTransitionTo(VerificationStatePending) {
+		t.Error("Invalid verification transitions")
+	}
+}
+
+func TestStatusTracker(t *testing.T) {
+	tracker := NewStatusTracker()
+	uri := "/v1/marketplaces/MP1/debits/WD1"
+
+	for _, status := range []DebitStatus{DebitStatusPending,
+		DebitStatusPending, DebitStatusSucceeded} {
+		if err := tracker.ObserveDebit(&Debit{Uri: uri, Status: status}); err != nil {
+			t.Fatalf("Unexpected error observing %v: %v", status, err)
+		}
+	}
+
+	// A late event still carries the pending debit
+	event := &Event{}
+	err := json.Unmarshal([]byte(`{
+		"id": "EV1",
+		"type": "debit.created",
+		"entity": {"_type": "debit", "uri": "`+uri+`", "status": "pending"}
+	}`), event)
+	if err != nil {
+		t.Fatalf("Failed to decode event: %v", err)
+	}
+
+	err = tracker.ObserveEvent(event)
+	var transitionErr *TransitionError
+	if !errors.Is(err, ErrInvalidTransition) || !errors.As(err, &transitionErr) ||
+		transitionErr.From != "succeeded" || transitionErr.To != "pending" {
+		t.Fatalf("Expected an invalid transition, got %v", err)
+	}
+
+	if tracker.Status(uri) != "succeeded" {
+		t.Fatalf("Expected the final status to be kept, got %v", tracker.Status(uri))
+	}
+
+	// Resources are tracked separately
+	credit := &Credit{Uri: "/v1/credits/CR1", Status: CreditStatusPaid}
+	if err := tracker.ObserveCredit(credit); err != nil {
+		t.Fatalf("Unexpected error observing credit: %v", err)
+	}
+
+	tracker.Forget(uri)
+	if err := tracker.ObserveEvent(event); err != nil || tracker.Status(uri) != "pending" {
+		t.Fatalf("Expected a forgotten debit to be tracked anew, got %v", err)
+	}
+}
+
+func TestVerificationState(t *testing.T) {
+	bankAccount := createNewBankAccount(t)
+
+	verification, err := VerifyBankAccount(bankAccount.Uri)
+	if err != nil {
+		t.Fatalf("Failed to verify bank account: %v", err)
+	}
+
+	if verification.State != VerificationStatePending {
+		t.Fatalf("Expected a pending verification, got %q", verification.State)
+	}
+
+	tracker := NewStatusTracker()
+	if err := tracker.ObserveVerification(verification); err != nil {
+		t.Fatalf("Unexpected error observing verification: %v", err)
+	}
+
+	verification.State = VerificationStateVerified
+	if err := tracker.ObserveVerification(verification); err != nil {
+		t.Fatalf("Unexpected error observing verification: %v", err)
+	}
+}
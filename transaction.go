@@ -0,0 +1,275 @@
+package balanced
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"time"
+)
+
+const (
+	transactionsUri = marketplaceUri + "/%v/transactions"
+)
+
+// Kinds of transactions, as returned by Transaction.TransactionKind.
+const (
+	TransactionKindDebit  = "debit"
+	TransactionKindCredit = "credit"
+	TransactionKindHold   = "hold"
+	TransactionKindRefund = "refund"
+)
+
+// The fields shared by every kind of transaction. *Debit, *Credit, *Hold and
+// *Refund implement it, use a type switch to get at the rest:
+//
+//	switch transaction := transaction.(type) {
+//	case *balanced.Debit:
+//		...
+//	}
+type Transaction interface {
+	// Resource type, i.e. TransactionKindDebit.
+	TransactionKind() string
+	// Amount in cents.
+	TransactionAmount() int
+	TransactionCreatedAt() time.Time
+	// Debits and credits report their Status. Holds are "void", "captured"
+	// or "active", refunds are always "succeeded".
+	TransactionStatus() string
+}
+
+func (d *Debit) TransactionKind() string         { return TransactionKindDebit }
+func (d *Debit) TransactionAmount() int          { return d.Amount }
+func (d *Debit) TransactionCreatedAt() time.Time { return d.CreatedAt }
+func (d *Debit) TransactionStatus() string       { return string(d.Status) }
+
+func (c *Credit) TransactionKind() string         { return TransactionKindCredit }
+func (c *Credit) TransactionAmount() int          { return c.Amount }
+func (c *Credit) TransactionCreatedAt() time.Time { return c.CreatedAt }
+func (c *Credit) TransactionStatus() string       { return string(c.Status) }
+
+func (h *Hold) TransactionKind() string         { return TransactionKindHold }
+func (h *Hold) TransactionAmount() int          { return h.Amount }
+func (h *Hold) TransactionCreatedAt() time.Time { return h.CreatedAt }
+
+func (h *Hold) TransactionStatus() string {
+	switch {
+	case h.IsVoid:
+		return "void"
+	case h.Debit != nil:
+		return "captured"
+	}
+
+	return "active"
+}
+
+func (r *Refund) TransactionKind() string         { return TransactionKindRefund }
+func (r *Refund) TransactionAmount() int          { return r.Amount }
+func (r *Refund) TransactionCreatedAt() time.Time { return r.CreatedAt }
+func (r *Refund) TransactionStatus() string       { return "succeeded" }
+
+// A transaction of a kind the package does not know about. Only the shared
+// fields are decoded.
+type otherTransaction struct {
+	Kind      string    `json:"_type"`
+	Amount    int       `json:"amount,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	Status    string    `json:"status,omitempty"`
+}
+
+func (o *otherTransaction) TransactionKind() string         { return o.Kind }
+func (o *otherTransaction) TransactionAmount() int          { return o.Amount }
+func (o *otherTransaction) TransactionCreatedAt() time.Time { return o.CreatedAt }
+func (o *otherTransaction) TransactionStatus() string       { return o.Status }
+
+// Creates an empty transaction for each kind.
+var transactionKinds = map[string]func() Transaction{
+	TransactionKindCredit: func() Transaction { return &Credit{} },
+	TransactionKindDebit:  func() Transaction { return &Debit{} },
+	TransactionKindHold:   func() Transaction { return &Hold{} },
+	TransactionKindRefund: func() Transaction { return &Refund{} },
+}
+
+// An item of a transactions list. Value holds the decoded *Debit, *Credit,
+// *Hold or *Refund; transactions of other kinds only carry the fields of the
+// Transaction interface.
+type TransactionItem struct {
+	Kind  string
+	Raw   json.RawMessage
+	Value Transaction
+}
+
+func (t *TransactionItem) UnmarshalJSON(data []byte) error {
+	*t = TransactionItem{}
+	t.Raw = append(json.RawMessage{}, data...)
+
+	other := &otherTransaction{}
+	if err := json.Unmarshal(data, other); err != nil {
+		return err
+	}
+	t.Kind = other.Kind
+
+	newValue, ok := transactionKinds[other.Kind]
+	if !ok {
+		t.Value = other
+		return nil
+	}
+
+	value := newValue()
+	if err := json.Unmarshal(data, value); err != nil {
+		return fmt.Errorf("Balanced API: Unable to decode %v transaction: %w",
+			other.Kind, err)
+	}
+	t.Value = value
+
+	return nil
+}
+
+func (t TransactionItem) MarshalJSON() ([]byte, error) {
+	if len(t.Raw) != 0 {
+		return t.Raw, nil
+	}
+
+	return json.Marshal(t.Value)
+}
+
+// Returns the transaction if it is a debit, nil otherwise.
+func (t *TransactionItem) Debit() *Debit {
+	debit, _ := t.Value.(*Debit)
+	return debit
+}
+
+// Returns the transaction if it is a credit, nil otherwise.
+func (t *TransactionItem) Credit() *Credit {
+	credit, _ := t.Value.(*Credit)
+	return credit
+}
+
+// Returns the transaction if it is a hold, nil otherwise.
+func (t *TransactionItem) Hold() *Hold {
+	hold, _ := t.Value.(*Hold)
+	return hold
+}
+
+// Returns the transaction if it is a refund, nil otherwise.
+func (t *TransactionItem) Refund() *Refund {
+	refund, _ := t.Value.(*Refund)
+	return refund
+}
+
+type ListOfTransactions = Page[TransactionItem]
+
+// Returns a list of every debit, credit, hold and refund of the marketplace.
+// The transactions are returned in sorted order, with the most recent
+// transactions appearing first.
+func (c *Client) ListTransactions(limit, offset int) (listOfTransactions *ListOfTransactions, err error) {
+	return c.ListTransactionsContext(context.Background(), limit, offset)
+}
+
+// ListTransactionsContext is like ListTransactions but carries the given context.
+func (c *Client) ListTransactionsContext(ctx context.Context, limit, offset int) (listOfTransactions *ListOfTransactions, err error) {
+	payload := defaultPayload(limit, offset)
+
+	uri := fmt.Sprintf(transactionsUri, c.MarketplaceId)
+
+	listOfTransactions = &ListOfTransactions{}
+	err = c.get(ctx, uri, payload, listOfTransactions)
+
+	return
+}
+
+// ListTransactions is a wrapper around DefaultClient.ListTransactions.
+func ListTransactions(limit, offset int) (listOfTransactions *ListOfTransactions, err error) {
+	return DefaultClient.ListTransactions(limit, offset)
+}
+
+// ListTransactionsContext is a wrapper around DefaultClient.ListTransactionsContext.
+func ListTransactionsContext(ctx context.Context, limit, offset int) (listOfTransactions *ListOfTransactions, err error) {
+	return DefaultClient.ListTransactionsContext(ctx, limit, offset)
+}
+
+// Returns the transactions selected by query, i.e. transactions created in a given
+// period or with a given meta value. See Query.
+func (c *Client) ListTransactionsWithQuery(query *Query) (listOfTransactions *ListOfTransactions, err error) {
+	return c.ListTransactionsWithQueryContext(context.Background(), query)
+}
+
+// ListTransactionsWithQueryContext is like ListTransactionsWithQuery but carries the given context.
+func (c *Client) ListTransactionsWithQueryContext(ctx context.Context, query *Query) (listOfTransactions *ListOfTransactions, err error) {
+	uri := fmt.Sprintf(transactionsUri, c.MarketplaceId)
+
+	return List[TransactionItem](ctx, c, uri, query)
+}
+
+// ListTransactionsWithQuery is a wrapper around DefaultClient.ListTransactionsWithQuery.
+func ListTransactionsWithQuery(query *Query) (listOfTransactions *ListOfTransactions, err error) {
+	return DefaultClient.ListTransactionsWithQuery(query)
+}
+
+// ListTransactionsWithQueryContext is a wrapper around DefaultClient.ListTransactionsWithQueryContext.
+func ListTransactionsWithQueryContext(ctx context.Context, query *Query) (listOfTransactions *ListOfTransactions, err error) {
+	return DefaultClient.ListTransactionsWithQueryContext(ctx, query)
+}
+
+// Returns a list of the transactions of a specific account, given its
+// transactions_uri. The transactions are returned in sorted order, with the
+// most recent transactions appearing first.
+func (c *Client) ListTransactionsForAccount(uri string, limit, offset int) (listOfTransactions *ListOfTransactions, err error) {
+	return c.ListTransactionsForAccountContext(context.Background(), uri,
+		limit, offset)
+}
+
+// ListTransactionsForAccountContext is like ListTransactionsForAccount but carries the given context.
+func (c *Client) ListTransactionsForAccountContext(ctx context.Context, uri string, limit, offset int) (listOfTransactions *ListOfTransactions, err error) {
+	payload := defaultPayload(limit, offset)
+
+	listOfTransactions = &ListOfTransactions{}
+	err = c.get(ctx, uri, payload, listOfTransactions)
+
+	return
+}
+
+// ListTransactionsForAccount is a wrapper around DefaultClient.ListTransactionsForAccount.
+func ListTransactionsForAccount(uri string, limit, offset int) (listOfTransactions *ListOfTransactions, err error) {
+	return DefaultClient.ListTransactionsForAccount(uri, limit, offset)
+}
+
+// ListTransactionsForAccountContext is a wrapper around DefaultClient.ListTransactionsForAccountContext.
+func ListTransactionsForAccountContext(ctx context.Context, uri string, limit, offset int) (listOfTransactions *ListOfTransactions, err error) {
+	return DefaultClient.ListTransactionsForAccountContext(ctx, uri, limit,
+		offset)
+}
+
+// Iterates over every transaction of the marketplace, most recent first.
+func (c *Client) IterateTransactions(ctx context.Context, options *IterOptions) iter.Seq2[Transaction, error] {
+	uri := fmt.Sprintf(transactionsUri, c.MarketplaceId)
+
+	return iterateTransactions(ctx, c, uri, options)
+}
+
+// IterateTransactions is a wrapper around DefaultClient.IterateTransactions.
+func IterateTransactions(ctx context.Context, options *IterOptions) iter.Seq2[Transaction, error] {
+	return DefaultClient.IterateTransactions(ctx, options)
+}
+
+// Iterates over every transaction of the account whose transactions_uri is
+// given, most recent first.
+func (c *Client) IterateTransactionsForAccount(ctx context.Context, uri string, options *IterOptions) iter.Seq2[Transaction, error] {
+	return iterateTransactions(ctx, c, uri, options)
+}
+
+// IterateTransactionsForAccount is a wrapper around DefaultClient.IterateTransactionsForAccount.
+func IterateTransactionsForAccount(ctx context.Context, uri string, options *IterOptions) iter.Seq2[Transaction, error] {
+	return DefaultClient.IterateTransactionsForAccount(ctx, uri, options)
+}
+
+// Iterates over the transactions list at uri, yielding the decoded values.
+func iterateTransactions(ctx context.Context, c *Client, uri string, options *IterOptions) iter.Seq2[Transaction, error] {
+	return func(yield func(Transaction, error) bool) {
+		for item, err := range Iterate[TransactionItem](ctx, c, uri, options) {
+			if !yield(item.Value, err) {
+				return
+			}
+		}
+	}
+}
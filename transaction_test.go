@@ -0,0 +1,112 @@
+package balanced
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestListTransactions(t *testing.T) {
+	account := createAccountWithCard(t, testVisaCard)
+
+	hold, err := CreateNewHold(account.HoldsUri, "", "", "", "", "", 900, nil)
+	if err != nil {
+		t.Fatalf("Failed to create hold: %v", err)
+	}
+	if _, err := VoidHold(hold.Uri, "", true); err != nil {
+		t.Fatalf("Failed to void hold: %v", err)
+	}
+
+	debit := createNewDebit(t, account)
+	refund, err := RefundDebit(debit.RefundsUri)
+	if err != nil {
+		t.Fatalf("Failed to refund debit: %v", err)
+	}
+
+	list, err := ListTransactionsForAccount(account.TransactionsUri, 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to list transactions: %v", err)
+	}
+
+	// Debiting a card creates a hold behind the scenes
+	kinds := []string{TransactionKindRefund, TransactionKindDebit,
+		TransactionKindHold, TransactionKindHold}
+	if len(list.Items) != len(kinds) {
+		t.Fatalf("Expected %v transactions, got %v", len(kinds), list.Items)
+	}
+	for i, kind := range kinds {
+		if list.Items[i].Kind != kind || list.Items[i].Value.TransactionKind() != kind {
+			t.Fatalf("Expected transaction %v to be a %v, got %v", i, kind,
+				list.Items[i].Kind)
+		}
+	}
+
+	if r := list.Items[0].Refund(); r == nil || r.Uri != refund.Uri ||
+		r.TransactionAmount() != debit.Amount {
+		t.Fatalf("Invalid refund listed: %v", list.Items[0].Value)
+	}
+	if d := list.Items[1].Debit(); d == nil || d.Uri != debit.Uri ||
+		d.TransactionStatus() != "succeeded" || d.TransactionCreatedAt().IsZero() {
+		t.Fatalf("Invalid debit listed: %v", list.Items[1].Value)
+	}
+	if h := list.Items[3].Hold(); h == nil || h.Uri != hold.Uri ||
+		h.TransactionStatus() != "void" {
+		t.Fatalf("Invalid hold listed: %v", list.Items[3].Value)
+	}
+
+	// The marketplace lists the account's transactions along with all others
+	found := 0
+	for transaction, err := range IterateTransactions(context.Background(),
+		&IterOptions{PageSize: 3}) {
+		if err != nil {
+			t.Fatalf("Failed to iterate over transactions: %v", err)
+		}
+
+		switch transaction := transaction.(type) {
+		case *Debit:
+			if transaction.Uri == debit.Uri {
+				found++
+			}
+		case *Refund:
+			if transaction.Uri == refund.Uri {
+				found++
+			}
+		}
+	}
+
+	if found != 2 {
+		t.Fatalf("Expected the debit and refund among the marketplace's transactions")
+	}
+}
+
+func TestTransactionItemDecoding(t *testing.T) {
+	data := `[
+		{"_type": "credit", "uri": "/v1/credits/CR1", "amount": 1000, "status": "pending"},
+		{"_type": "reversal", "uri": "/v1/reversals/RV1", "amount": 500, "status": "succeeded"}
+	]`
+
+	items := []TransactionItem{}
+	if err := json.Unmarshal([]byte(data), &items); err != nil {
+		t.Fatalf("Failed to decode transactions: %v", err)
+	}
+
+	credit := items[0].Credit()
+	if credit == nil || credit.Uri != "/v1/credits/CR1" ||
+		credit.TransactionStatus() != "pending" {
+		t.Fatalf("Invalid credit decoded: %v", items[0])
+	}
+
+	// Unknown kinds still carry the shared fields
+	other := items[1].Value
+	if other.TransactionKind() != "reversal" || other.TransactionAmount() != 500 ||
+		other.TransactionStatus() != "succeeded" || items[1].Debit() != nil {
+		t.Fatalf("Invalid transaction decoded: %v", items[1])
+	}
+
+	// The fields that were not decoded are kept
+	encoded, err := json.Marshal(items[1])
+	if err != nil || !strings.Contains(string(encoded), "/v1/reversals/RV1") {
+		t.Fatalf("Expected the transaction to be encoded as received, got %s", encoded)
+	}
+}